@@ -11,7 +11,7 @@ import (
 
 func main() {
 	if len(os.Args) != 3 {
-		exit(0, fmt.Sprintf("Usage: %s <package-org> <component-name>"))
+		exit(1, fmt.Sprintf("Usage: %s <package-org> <component-name>", os.Args[0]))
 	}
 
 	componentTemplateRoot := "component-template/component"
@@ -46,10 +46,10 @@ func main() {
 				return nil
 			}
 
-			switch path {
-			case "main.go":
+			switch {
+			case filepath.Ext(path) == ".go":
 				err = generateFile(packageOrg, componentName, srcFilePath, filepath.Join(componentDir, path))
-			case "wit/component.wit":
+			case path == "wit/component.wit":
 				err = generateFile(packageOrg, componentName, srcFilePath, filepath.Join(componentDir, "wit", componentName+".wit"))
 			default:
 				err = copyFile(srcFilePath, filepath.Join(componentDir, path))
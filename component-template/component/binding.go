@@ -0,0 +1,49 @@
+//go:build !hosttest
+
+package main
+
+import (
+	"golem-go-project/components/component-name/binding"
+	"golem-go-project/lib/rpc"
+)
+
+func init() {
+	binding.SetExportsPackageOrgComponentNameComponentNameApi(exports{&Impl{}})
+}
+
+// exports adapts Impl to the generated exports interface, converting the returned errors to the error variant
+type exports struct {
+	*Impl
+}
+
+func (e exports) Add(value uint64) binding.Result[struct{}, binding.PackageOrgComponentNameComponentNameApiError] {
+	err := e.Impl.Add(value)
+	if err != nil {
+		return binding.Err[struct{}](bindingError(err))
+	}
+	return binding.Ok[struct{}, binding.PackageOrgComponentNameComponentNameApiError](struct{}{})
+}
+
+func (e exports) Get() binding.Result[uint64, binding.PackageOrgComponentNameComponentNameApiError] {
+	counter, err := e.Impl.Get()
+	if err != nil {
+		return binding.Err[uint64](bindingError(err))
+	}
+	return binding.Ok[uint64, binding.PackageOrgComponentNameComponentNameApiError](counter)
+}
+
+func bindingError(err error) binding.PackageOrgComponentNameComponentNameApiError {
+	rpcErr := rpc.AsError(err)
+	switch rpcErr.Kind {
+	case rpc.ErrorKindProtocolError:
+		return binding.PackageOrgComponentNameComponentNameApiErrorProtocolError(rpcErr.Message)
+	case rpc.ErrorKindDenied:
+		return binding.PackageOrgComponentNameComponentNameApiErrorDenied(rpcErr.Message)
+	case rpc.ErrorKindNotFound:
+		return binding.PackageOrgComponentNameComponentNameApiErrorNotFound(rpcErr.Message)
+	case rpc.ErrorKindConfigError:
+		return binding.PackageOrgComponentNameComponentNameApiErrorConfigError(rpcErr.Message)
+	default:
+		return binding.PackageOrgComponentNameComponentNameApiErrorRemoteInternalError(rpcErr.Message)
+	}
+}
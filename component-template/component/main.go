@@ -1,29 +1,39 @@
 package main
 
 import (
-	"github.com/golemcloud/golem-go/std"
+	"fmt"
 
-	"golem-go-project/components/component-name/binding"
+	// NOTE: use the lib folder to create common packages used by multiple components
+	"golem-go-project/lib/cfg"
+	"golem-go-project/lib/stdinit"
 )
 
-func init() {
-	binding.SetExportsPackageOrgComponentNameComponentNameApi(&Impl{})
-}
+// startup checks the configuration of the dependencies at the first invocation
+var startup = cfg.NewStartup(cfg.DependenciesOf("component-name"))
 
 type Impl struct {
 	counter uint64
 }
 
-func (i *Impl) Add(value uint64) {
-	std.Init(std.Packages{Os: true, NetHttp: true})
+func (i *Impl) Add(value uint64) error {
+	stdinit.Init()
+	err := startup.Init()
+	if err != nil {
+		return fmt.Errorf("component-name: add %d failed, %w", value, err)
+	}
 
 	i.counter += value
+	return nil
 }
 
-func (i *Impl) Get() uint64 {
-	std.Init(std.Packages{Os: true, NetHttp: true})
+func (i *Impl) Get() (uint64, error) {
+	stdinit.Init()
+	err := startup.Init()
+	if err != nil {
+		return 0, fmt.Errorf("component-name: get failed, %w", err)
+	}
 
-	return i.counter
+	return i.counter, nil
 }
 
 func main() {}
@@ -0,0 +1,26 @@
+//go:build hosttest
+
+package main
+
+import (
+	"testing"
+)
+
+func TestAddIncrementsCounter(t *testing.T) {
+	t.Cleanup(startup.Reset)
+
+	impl := &Impl{}
+	for _, value := range []uint64{3, 2} {
+		if err := impl.Add(value); err != nil {
+			t.Fatalf("%+v", err)
+		}
+	}
+
+	counter, err := impl.Get()
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	if counter != 5 {
+		t.Fatalf("Expected counter: 5, actual: %d", counter)
+	}
+}
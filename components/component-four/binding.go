@@ -0,0 +1,92 @@
+//go:build !hosttest
+
+package main
+
+import (
+	"errors"
+	"fmt"
+
+	"golem-go-project/components/component-four/binding"
+	"golem-go-project/components/component-four/binding/keyvalue"
+)
+
+func init() {
+	binding.SetExportsGolemComponentFourComponentFourApi(exports{&Impl{}})
+}
+
+// exports adapts Impl to the generated exports interface, converting the returned errors to the string error results
+type exports struct {
+	*Impl
+}
+
+func (e exports) Add(key string, value uint64) binding.Result[struct{}, string] {
+	err := e.Impl.Add(key, value)
+	if err != nil {
+		return binding.Err[struct{}](err.Error())
+	}
+	return binding.Ok[struct{}, string](struct{}{})
+}
+
+func (e exports) Get(key string) binding.Result[uint64, string] {
+	counter, err := e.Impl.Get(key)
+	if err != nil {
+		return binding.Err[uint64](err.Error())
+	}
+	return binding.Ok[uint64, string](counter)
+}
+
+type keyvalueBucket struct {
+	bucket keyvalue.WasiKeyvalue0_1_0_TypesBucket
+}
+
+func openBucket(name string) (bucket, error) {
+	result := keyvalue.StaticBucketOpenBucket(name)
+	if result.IsErr() {
+		return nil, fmt.Errorf("openBucket failed for %s, %w", name, keyvalueError(result.UnwrapErr()))
+	}
+	return &keyvalueBucket{bucket: result.Unwrap()}, nil
+}
+
+func (b *keyvalueBucket) Get(key string) ([]byte, bool, error) {
+	result := keyvalue.WasiKeyvalue0_1_0_EventualGet(b.bucket, key)
+	if result.IsErr() {
+		return nil, false, keyvalueError(result.UnwrapErr())
+	}
+	incomingValue := result.Unwrap()
+	if incomingValue.IsNone() {
+		return nil, false, nil
+	}
+	defer incomingValue.Unwrap().Drop()
+
+	body := incomingValue.Unwrap().IncomingValueConsumeSync()
+	if body.IsErr() {
+		return nil, false, keyvalueError(body.UnwrapErr())
+	}
+	return body.Unwrap(), true, nil
+}
+
+func (b *keyvalueBucket) Set(key string, value []byte) error {
+	outgoingValue := keyvalue.StaticOutgoingValueNewOutgoingValue()
+	defer outgoingValue.Drop()
+
+	writeResult := outgoingValue.OutgoingValueWriteBodySync(value)
+	if writeResult.IsErr() {
+		return keyvalueError(writeResult.UnwrapErr())
+	}
+
+	setResult := keyvalue.WasiKeyvalue0_1_0_EventualSet(b.bucket, key, outgoingValue)
+	if setResult.IsErr() {
+		return keyvalueError(setResult.UnwrapErr())
+	}
+	return nil
+}
+
+func (b *keyvalueBucket) Drop() {
+	b.bucket.Drop()
+}
+
+// keyvalueError converts the keyvalue error resource to an error, and drops the resource
+func keyvalueError(err keyvalue.WasiKeyvalue0_1_0_TypesError) error {
+	defer err.Drop()
+	return errors.New(err.Trace())
+}
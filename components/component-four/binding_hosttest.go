@@ -0,0 +1,54 @@
+//go:build hosttest
+
+package main
+
+import (
+	"fmt"
+	"sync"
+)
+
+// fakeBuckets holds the in-memory keyvalue buckets by name, which are used instead of wasi:keyvalue in host tests
+var fakeBuckets = struct {
+	mu      sync.Mutex
+	buckets map[string]map[string][]byte
+}{buckets: make(map[string]map[string][]byte)}
+
+// fakeOpenBucketErr is returned by openBucket when set
+var fakeOpenBucketErr error
+
+type fakeBucket struct {
+	name string
+}
+
+func openBucket(name string) (bucket, error) {
+	if fakeOpenBucketErr != nil {
+		return nil, fmt.Errorf("openBucket failed for %s, %w", name, fakeOpenBucketErr)
+	}
+	return &fakeBucket{name: name}, nil
+}
+
+func (b *fakeBucket) Get(key string) ([]byte, bool, error) {
+	fakeBuckets.mu.Lock()
+	defer fakeBuckets.mu.Unlock()
+	value, ok := fakeBuckets.buckets[b.name][key]
+	return value, ok, nil
+}
+
+func (b *fakeBucket) Set(key string, value []byte) error {
+	fakeBuckets.mu.Lock()
+	defer fakeBuckets.mu.Unlock()
+	if fakeBuckets.buckets[b.name] == nil {
+		fakeBuckets.buckets[b.name] = make(map[string][]byte)
+	}
+	fakeBuckets.buckets[b.name][key] = value
+	return nil
+}
+
+func (b *fakeBucket) Drop() {}
+
+func resetFakeBuckets() {
+	fakeBuckets.mu.Lock()
+	defer fakeBuckets.mu.Unlock()
+	fakeBuckets.buckets = make(map[string]map[string][]byte)
+	fakeOpenBucketErr = nil
+}
@@ -0,0 +1,75 @@
+package main
+
+import (
+	"encoding/binary"
+	"fmt"
+
+	"golem-go-project/lib/stdinit"
+)
+
+// bucketName is the name of the keyvalue bucket storing the counters
+var bucketName = "component-four"
+
+// NOTE: the keyvalue bucket is opened through this interface, so it can be replaced with a fake in host tests,
+// see binding.go and binding_hosttest.go
+type bucket interface {
+	Get(key string) ([]byte, bool, error)
+	Set(key string, value []byte) error
+	Drop()
+}
+
+type Impl struct{}
+
+func (i *Impl) Add(key string, value uint64) error {
+	stdinit.Init()
+
+	bucket, err := openBucket(bucketName)
+	if err != nil {
+		return fmt.Errorf("component-four: add %d failed for %s, %w", value, key, err)
+	}
+	defer bucket.Drop()
+
+	counter, err := getCounter(bucket, key)
+	if err != nil {
+		return fmt.Errorf("component-four: add %d failed for %s, %w", value, key, err)
+	}
+
+	err = bucket.Set(key, binary.BigEndian.AppendUint64(nil, counter+value))
+	if err != nil {
+		return fmt.Errorf("component-four: add %d failed for %s, set failed, %w", value, key, err)
+	}
+	return nil
+}
+
+func (i *Impl) Get(key string) (uint64, error) {
+	stdinit.Init()
+
+	bucket, err := openBucket(bucketName)
+	if err != nil {
+		return 0, fmt.Errorf("component-four: get failed for %s, %w", key, err)
+	}
+	defer bucket.Drop()
+
+	counter, err := getCounter(bucket, key)
+	if err != nil {
+		return 0, fmt.Errorf("component-four: get failed for %s, %w", key, err)
+	}
+
+	return counter, nil
+}
+
+func getCounter(bucket bucket, key string) (uint64, error) {
+	value, ok, err := bucket.Get(key)
+	if err != nil {
+		return 0, fmt.Errorf("getCounter: get failed for %s, %w", key, err)
+	}
+	if !ok {
+		return 0, nil
+	}
+	if len(value) != 8 {
+		return 0, fmt.Errorf("getCounter: invalid counter value for %s, expected 8 bytes, got %d", key, len(value))
+	}
+	return binary.BigEndian.Uint64(value), nil
+}
+
+func main() {}
@@ -0,0 +1,90 @@
+//go:build hosttest
+
+package main
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestAddIncrementsCounterPerKey(t *testing.T) {
+	resetFakeBuckets()
+
+	impl := &Impl{}
+	mustAdd(t, impl, "a", 3)
+	mustAdd(t, impl, "a", 2)
+	mustAdd(t, impl, "b", 1)
+
+	if actual := mustGet(t, impl, "a"); actual != 5 {
+		t.Fatalf("Expected counter for a: 5, actual: %d", actual)
+	}
+	if actual := mustGet(t, impl, "b"); actual != 1 {
+		t.Fatalf("Expected counter for b: 1, actual: %d", actual)
+	}
+	if actual := mustGet(t, impl, "c"); actual != 0 {
+		t.Fatalf("Expected counter for c: 0, actual: %d", actual)
+	}
+}
+
+func TestCountersAreStoredInBucket(t *testing.T) {
+	resetFakeBuckets()
+
+	mustAdd(t, &Impl{}, "a", 3)
+
+	if actual := mustGet(t, &Impl{}, "a"); actual != 3 {
+		t.Fatalf("Expected counter from a new instance: 3, actual: %d", actual)
+	}
+}
+
+func TestAddReturnsErrorWhenOpenBucketFails(t *testing.T) {
+	resetFakeBuckets()
+
+	impl := &Impl{}
+	fakeOpenBucketErr = errors.New("bucket unavailable")
+	err := impl.Add("a", 3)
+	fakeOpenBucketErr = nil
+
+	if err == nil ||
+		!strings.Contains(err.Error(), "component-four: add 3 failed for a") ||
+		!strings.Contains(err.Error(), "bucket unavailable") {
+		t.Fatalf("Expected open bucket error with context, actual: %+v", err)
+	}
+	if actual := mustGet(t, impl, "a"); actual != 0 {
+		t.Fatalf("Expected counter: 0, actual: %d", actual)
+	}
+}
+
+func TestGetReturnsErrorOnInvalidCounterValue(t *testing.T) {
+	resetFakeBuckets()
+
+	bucket, _ := openBucket(bucketName)
+	_ = bucket.Set("a", []byte{1})
+
+	counter, err := (&Impl{}).Get("a")
+	if err == nil || !strings.Contains(err.Error(), "component-four: get failed for a") {
+		t.Fatalf("Expected error for invalid counter value, actual: %+v", err)
+	}
+	if counter != 0 {
+		t.Fatalf("Expected counter: 0, actual: %d", counter)
+	}
+	if err := (&Impl{}).Add("a", 1); err == nil {
+		t.Fatalf("Expected add error for invalid counter value")
+	}
+}
+
+func mustAdd(t *testing.T, impl *Impl, key string, value uint64) {
+	t.Helper()
+	if err := impl.Add(key, value); err != nil {
+		t.Fatalf("%+v", err)
+	}
+}
+
+func mustGet(t *testing.T, impl *Impl, key string) uint64 {
+	t.Helper()
+	counter, err := impl.Get(key)
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	return counter
+}
@@ -0,0 +1,49 @@
+//go:build !hosttest
+
+package main
+
+import (
+	"golem-go-project/components/component-one/binding"
+	"golem-go-project/lib/rpc"
+)
+
+func init() {
+	binding.SetExportsGolemComponentOneComponentOneApi(exports{&Impl{}})
+}
+
+// exports adapts Impl to the generated exports interface, converting the returned errors to the error variant
+type exports struct {
+	*Impl
+}
+
+func (e exports) Add(value uint64) binding.Result[struct{}, binding.GolemComponentOneComponentOneApiError] {
+	err := e.Impl.Add(value)
+	if err != nil {
+		return binding.Err[struct{}](bindingError(err))
+	}
+	return binding.Ok[struct{}, binding.GolemComponentOneComponentOneApiError](struct{}{})
+}
+
+func (e exports) Get() binding.Result[uint64, binding.GolemComponentOneComponentOneApiError] {
+	counter, err := e.Impl.Get()
+	if err != nil {
+		return binding.Err[uint64](bindingError(err))
+	}
+	return binding.Ok[uint64, binding.GolemComponentOneComponentOneApiError](counter)
+}
+
+func bindingError(err error) binding.GolemComponentOneComponentOneApiError {
+	rpcErr := rpc.AsError(err)
+	switch rpcErr.Kind {
+	case rpc.ErrorKindProtocolError:
+		return binding.GolemComponentOneComponentOneApiErrorProtocolError(rpcErr.Message)
+	case rpc.ErrorKindDenied:
+		return binding.GolemComponentOneComponentOneApiErrorDenied(rpcErr.Message)
+	case rpc.ErrorKindNotFound:
+		return binding.GolemComponentOneComponentOneApiErrorNotFound(rpcErr.Message)
+	case rpc.ErrorKindConfigError:
+		return binding.GolemComponentOneComponentOneApiErrorConfigError(rpcErr.Message)
+	default:
+		return binding.GolemComponentOneComponentOneApiErrorRemoteInternalError(rpcErr.Message)
+	}
+}
@@ -1,62 +1,57 @@
 package main
 
 import (
+	"context"
 	"fmt"
 
-	"github.com/golemcloud/golem-go/golemhost"
-	"github.com/golemcloud/golem-go/std"
-
-	"golem-go-project/components/component-one/binding"
 	// NOTE: use the lib folder to create common packages used by multiple components
 	"golem-go-project/lib/cfg"
+	"golem-go-project/lib/clients/componentthree"
+	"golem-go-project/lib/clients/componenttwo"
+	"golem-go-project/lib/host"
+	"golem-go-project/lib/rpc"
+	"golem-go-project/lib/stdinit"
 )
 
-func init() {
-	binding.SetExportsGolemComponentOneComponentOneApi(&Impl{})
-}
+// startup checks the configuration of the dependencies at the first invocation
+var startup = cfg.NewStartup(cfg.DependenciesOf("component-one"))
 
 type Impl struct {
 	counter uint64
 }
 
-func (i *Impl) Add(value uint64) {
-	std.Init(std.Packages{Os: true, NetHttp: true})
-
-	selfWorkerName := golemhost.GetSelfMetadata().WorkerId.WorkerName
-
-	{
-		componentTwoWorkerURI, err := cfg.ComponentTwoWorkerURI(selfWorkerName)
-		if err != nil {
-			fmt.Printf("%+v\n", err)
-			return
-		}
-
-		fmt.Printf("Calling %s...\n", componentTwoWorkerURI.Value)
-		componentTwo := binding.NewComponentTwoApi(binding.GolemRpc0_1_0_TypesUri(componentTwoWorkerURI))
-		defer componentTwo.Drop()
-		componentTwo.BlockingAdd(value)
+func (i *Impl) Add(value uint64) error {
+	stdinit.Init()
+	err := startup.Init()
+	if err != nil {
+		return fmt.Errorf("component-one: add %d failed, %w", value, err)
 	}
 
-	{
-		componentThreeWorkerURI, err := cfg.ComponentThreeWorkerURI(selfWorkerName)
-		if err != nil {
-			fmt.Printf("%+v\n", err)
-			return
-		}
-
-		fmt.Printf("Calling %s...\n", componentThreeWorkerURI.Value)
-		componentThree := binding.NewComponentThreeApi(binding.GolemRpc0_1_0_TypesUri(componentThreeWorkerURI))
-		defer componentThree.Drop()
-		componentThree.BlockingAdd(value)
+	ctx := context.Background()
+	selfWorkerName := host.SelfWorkerName()
+
+	fmt.Printf("Calling component-two and component-three workers routed for %s...\n", selfWorkerName)
+	err = rpc.AwaitAll(
+		ctx,
+		componenttwo.Routed(selfWorkerName).AddAsync(ctx, value),
+		componentthree.Routed(selfWorkerName).AddAsync(ctx, value),
+	)
+	if err != nil {
+		return fmt.Errorf("component-one: add %d failed for %s, %w", value, selfWorkerName, err)
 	}
 
 	i.counter += value
+	return nil
 }
 
-func (i *Impl) Get() uint64 {
-	std.Init(std.Packages{Os: true, NetHttp: true})
+func (i *Impl) Get() (uint64, error) {
+	stdinit.Init()
+	err := startup.Init()
+	if err != nil {
+		return 0, fmt.Errorf("component-one: get failed, %w", err)
+	}
 
-	return i.counter
+	return i.counter, nil
 }
 
 func main() {}
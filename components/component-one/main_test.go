@@ -0,0 +1,147 @@
+//go:build hosttest
+
+package main
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"golem-go-project/lib/cfg"
+	"golem-go-project/lib/host"
+	"golem-go-project/lib/rpc"
+)
+
+func TestAddCallsComponentTwoAndThree(t *testing.T) {
+	componentTwo, componentThree := registerFakeCounters(t, "test-worker")
+
+	impl := &Impl{}
+	mustAdd(t, impl, 3)
+	mustAdd(t, impl, 2)
+
+	if actual := mustGet(t, impl); actual != 5 {
+		t.Fatalf("Expected counter: 5, actual: %d", actual)
+	}
+	if componentTwo.Counter != 5 {
+		t.Fatalf("Expected component-two counter: 5, actual: %d", componentTwo.Counter)
+	}
+	if componentThree.Counter != 5 {
+		t.Fatalf("Expected component-three counter: 5, actual: %d", componentThree.Counter)
+	}
+}
+
+func TestAddReturnsErrorOfComponentTwo(t *testing.T) {
+	componentTwo, componentThree := registerFakeCounters(t, "test-worker")
+	componentTwo.Err = &rpc.Error{Kind: rpc.ErrorKindNotFound, Message: "component-two worker test-worker not found"}
+
+	impl := &Impl{}
+	err := impl.Add(3)
+
+	var rpcErr *rpc.Error
+	if !errors.As(err, &rpcErr) || rpcErr.Kind != rpc.ErrorKindNotFound {
+		t.Fatalf("Expected not-found error, actual: %+v", err)
+	}
+	if asErr := rpc.AsError(err); asErr.Kind != rpc.ErrorKindNotFound ||
+		!strings.Contains(asErr.Message, "component-one: add 3 failed for test-worker") ||
+		!strings.Contains(asErr.Message, "component-two worker test-worker not found") {
+		t.Fatalf("Expected error with context, actual: %+v", asErr)
+	}
+	if actual := mustGet(t, impl); actual != 0 {
+		t.Fatalf("Expected counter: 0, actual: %d", actual)
+	}
+	if componentThree.Counter != 3 {
+		t.Fatalf("Expected component-three counter: 3, actual: %d", componentThree.Counter)
+	}
+}
+
+func TestAddCallsComponentThreeWhenComponentTwoFails(t *testing.T) {
+	workerName := "test-worker"
+	host.SetSelfWorkerName(workerName)
+	t.Setenv("COMPONENT_TWO_ID", uuid.New().String())
+	t.Setenv("COMPONENT_THREE_ID", uuid.New().String())
+	t.Cleanup(startup.Reset)
+
+	rpc.RegisterFakeWorker(
+		mustWorkerURI(t, cfg.ComponentTwoWorkerURI, workerName),
+		func(functionName string, params []any) ([]any, error) {
+			return nil, &rpc.Error{Kind: rpc.ErrorKindRemoteInternalError, Message: "failed"}
+		},
+	)
+	componentThree := &rpc.FakeCounter{}
+	rpc.RegisterFakeWorker(mustWorkerURI(t, cfg.ComponentThreeWorkerURI, workerName), componentThree.Handle)
+	t.Cleanup(rpc.ResetFakeWorkers)
+
+	impl := &Impl{}
+	err := impl.Add(3)
+
+	if asErr := rpc.AsError(err); asErr.Kind != rpc.ErrorKindRemoteInternalError {
+		t.Fatalf("Expected remote-internal-error, actual: %+v", err)
+	}
+	if actual := mustGet(t, impl); actual != 0 {
+		t.Fatalf("Expected counter: 0, actual: %d", actual)
+	}
+	if componentThree.Counter != 3 {
+		t.Fatalf("Expected component-three counter: 3, actual: %d", componentThree.Counter)
+	}
+}
+
+func TestAddWithoutComponentIDsReturnsConfigError(t *testing.T) {
+	t.Setenv("COMPONENT_TWO_ID", "")
+	t.Setenv("COMPONENT_THREE_ID", "not-a-uuid")
+	t.Cleanup(startup.Reset)
+
+	impl := &Impl{}
+	err := impl.Add(3)
+
+	if asErr := rpc.AsError(err); asErr.Kind != rpc.ErrorKindConfigError ||
+		!strings.Contains(asErr.Message, "invalid configuration, 2 missing or invalid value(s)") ||
+		!strings.Contains(asErr.Message, "COMPONENT_TWO_ID (component-two): missing required value") ||
+		!strings.Contains(asErr.Message, "COMPONENT_THREE_ID (component-three): expected UUID") {
+		t.Fatalf("Expected config-error listing all invalid values, actual: %+v", err)
+	}
+	if _, err := impl.Get(); rpc.AsError(err).Kind != rpc.ErrorKindConfigError ||
+		!strings.HasPrefix(rpc.AsError(err).Message, "component-one: get failed") {
+		t.Fatalf("Expected config-error from get, actual: %+v", err)
+	}
+}
+
+func registerFakeCounters(t *testing.T, workerName string) (*rpc.FakeCounter, *rpc.FakeCounter) {
+	host.SetSelfWorkerName(workerName)
+	t.Setenv("COMPONENT_TWO_ID", uuid.New().String())
+	t.Setenv("COMPONENT_THREE_ID", uuid.New().String())
+	t.Cleanup(startup.Reset)
+
+	componentTwo := &rpc.FakeCounter{Results: true}
+	rpc.RegisterFakeWorker(mustWorkerURI(t, cfg.ComponentTwoWorkerURI, workerName), componentTwo.Handle)
+	componentThree := &rpc.FakeCounter{}
+	rpc.RegisterFakeWorker(mustWorkerURI(t, cfg.ComponentThreeWorkerURI, workerName), componentThree.Handle)
+	t.Cleanup(rpc.ResetFakeWorkers)
+
+	return componentTwo, componentThree
+}
+
+func mustAdd(t *testing.T, impl *Impl, value uint64) {
+	t.Helper()
+	if err := impl.Add(value); err != nil {
+		t.Fatalf("%+v", err)
+	}
+}
+
+func mustGet(t *testing.T, impl *Impl) uint64 {
+	t.Helper()
+	counter, err := impl.Get()
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	return counter
+}
+
+func mustWorkerURI(t *testing.T, workerURI func(workerName string) (cfg.URI, error), workerName string) cfg.URI {
+	uri, err := workerURI(workerName)
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	return uri
+}
@@ -0,0 +1,11 @@
+//go:build !hosttest
+
+package main
+
+import (
+	"golem-go-project/components/component-three/binding"
+)
+
+func init() {
+	binding.SetExportsGolemComponentThreeComponentThreeApi(&Impl{})
+}
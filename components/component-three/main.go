@@ -1,27 +1,21 @@
 package main
 
 import (
-	"github.com/golemcloud/golem-go/std"
-
-	"golem-go-project/components/component-three/binding"
+	"golem-go-project/lib/stdinit"
 )
 
-func init() {
-	binding.SetExportsGolemComponentThreeComponentThreeApi(&Impl{})
-}
-
 type Impl struct {
 	counter uint64
 }
 
 func (i *Impl) Add(value uint64) {
-	std.Init(std.Packages{Os: true, NetHttp: true})
+	stdinit.Init()
 
 	i.counter += value
 }
 
 func (i *Impl) Get() uint64 {
-	std.Init(std.Packages{Os: true, NetHttp: true})
+	stdinit.Init()
 
 	return i.counter
 }
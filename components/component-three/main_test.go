@@ -0,0 +1,17 @@
+//go:build hosttest
+
+package main
+
+import (
+	"testing"
+)
+
+func TestAddIncrementsCounter(t *testing.T) {
+	impl := &Impl{}
+	impl.Add(3)
+	impl.Add(2)
+
+	if actual := impl.Get(); actual != 5 {
+		t.Fatalf("Expected counter: 5, actual: %d", actual)
+	}
+}
@@ -0,0 +1,57 @@
+//go:build !hosttest
+
+package main
+
+import (
+	"golem-go-project/components/component-two/binding"
+	"golem-go-project/lib/rpc"
+)
+
+func init() {
+	binding.SetExportsGolemComponentTwoComponentTwoApi(exports{&Impl{}})
+}
+
+// exports adapts Impl to the generated exports interface, converting the returned errors to the error variant
+type exports struct {
+	*Impl
+}
+
+func (e exports) Add(value uint64) binding.Result[struct{}, binding.GolemComponentTwoComponentTwoApiError] {
+	err := e.Impl.Add(value)
+	if err != nil {
+		return binding.Err[struct{}](bindingError(err))
+	}
+	return binding.Ok[struct{}, binding.GolemComponentTwoComponentTwoApiError](struct{}{})
+}
+
+func (e exports) AddAndForget(value uint64) binding.Result[struct{}, binding.GolemComponentTwoComponentTwoApiError] {
+	err := e.Impl.AddAndForget(value)
+	if err != nil {
+		return binding.Err[struct{}](bindingError(err))
+	}
+	return binding.Ok[struct{}, binding.GolemComponentTwoComponentTwoApiError](struct{}{})
+}
+
+func (e exports) Get() binding.Result[uint64, binding.GolemComponentTwoComponentTwoApiError] {
+	counter, err := e.Impl.Get()
+	if err != nil {
+		return binding.Err[uint64](bindingError(err))
+	}
+	return binding.Ok[uint64, binding.GolemComponentTwoComponentTwoApiError](counter)
+}
+
+func bindingError(err error) binding.GolemComponentTwoComponentTwoApiError {
+	rpcErr := rpc.AsError(err)
+	switch rpcErr.Kind {
+	case rpc.ErrorKindProtocolError:
+		return binding.GolemComponentTwoComponentTwoApiErrorProtocolError(rpcErr.Message)
+	case rpc.ErrorKindDenied:
+		return binding.GolemComponentTwoComponentTwoApiErrorDenied(rpcErr.Message)
+	case rpc.ErrorKindNotFound:
+		return binding.GolemComponentTwoComponentTwoApiErrorNotFound(rpcErr.Message)
+	case rpc.ErrorKindConfigError:
+		return binding.GolemComponentTwoComponentTwoApiErrorConfigError(rpcErr.Message)
+	default:
+		return binding.GolemComponentTwoComponentTwoApiErrorRemoteInternalError(rpcErr.Message)
+	}
+}
@@ -1,49 +1,68 @@
 package main
 
 import (
+	"context"
 	"fmt"
 
-	"github.com/golemcloud/golem-go/golemhost"
-	"github.com/golemcloud/golem-go/std"
-
-	"golem-go-project/components/component-two/binding"
 	// NOTE: use the lib folder to create common packages used by multiple components
 	"golem-go-project/lib/cfg"
+	"golem-go-project/lib/clients/componentthree"
+	"golem-go-project/lib/host"
+	"golem-go-project/lib/stdinit"
 )
 
-func init() {
-	binding.SetExportsGolemComponentTwoComponentTwoApi(&Impl{})
-}
+// startup checks the configuration of the dependencies at the first invocation
+var startup = cfg.NewStartup(cfg.DependenciesOf("component-two"))
 
 type Impl struct {
 	counter uint64
 }
 
-func (i *Impl) Add(value uint64) {
-	std.Init(std.Packages{Os: true, NetHttp: true})
+func (i *Impl) Add(value uint64) error {
+	stdinit.Init()
+	err := startup.Init()
+	if err != nil {
+		return fmt.Errorf("component-two: add %d failed, %w", value, err)
+	}
 
-	selfWorkerName := golemhost.GetSelfMetadata().WorkerId.WorkerName
+	selfWorkerName := host.SelfWorkerName()
+
+	fmt.Printf("Calling component-three worker routed for %s...\n", selfWorkerName)
+	err = componentthree.Routed(selfWorkerName).Add(context.Background(), value)
+	if err != nil {
+		return fmt.Errorf("component-two: add %d failed for %s, %w", value, selfWorkerName, err)
+	}
 
-	{
-		componentThreeWorkerURI, err := cfg.ComponentThreeWorkerURI(selfWorkerName)
-		if err != nil {
-			fmt.Printf("%+v\n", err)
-			return
-		}
+	i.counter += value
+	return nil
+}
 
-		fmt.Printf("Calling %s...\n", componentThreeWorkerURI.Value)
-		componentThree := binding.NewComponentThreeApi(binding.GolemRpc0_1_0_TypesUri(componentThreeWorkerURI))
-		defer componentThree.Drop()
-		componentThree.BlockingAdd(value)
+// AddAndForget is like Add, but it does not wait for component-three, which only mirrors the counters, so only the
+// config errors are returned, and the delivery errors of component-three are logged
+func (i *Impl) AddAndForget(value uint64) error {
+	stdinit.Init()
+	err := startup.Init()
+	if err != nil {
+		return fmt.Errorf("component-two: add-and-forget %d failed, %w", value, err)
 	}
 
+	selfWorkerName := host.SelfWorkerName()
+
+	fmt.Printf("Calling component-three worker routed for %s without waiting...\n", selfWorkerName)
+	componentthree.Routed(selfWorkerName).AddFireAndForget(context.Background(), value)
+
 	i.counter += value
+	return nil
 }
 
-func (i *Impl) Get() uint64 {
-	std.Init(std.Packages{Os: true, NetHttp: true})
+func (i *Impl) Get() (uint64, error) {
+	stdinit.Init()
+	err := startup.Init()
+	if err != nil {
+		return 0, fmt.Errorf("component-two: get failed, %w", err)
+	}
 
-	return i.counter
+	return i.counter, nil
 }
 
 func main() {}
@@ -0,0 +1,248 @@
+//go:build hosttest
+
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"golem-go-project/lib/cfg"
+	"golem-go-project/lib/host"
+	"golem-go-project/lib/rpc"
+)
+
+func TestAddCallsComponentThree(t *testing.T) {
+	workerName := "test-worker"
+	host.SetSelfWorkerName(workerName)
+	t.Setenv("COMPONENT_THREE_ID", uuid.New().String())
+	t.Cleanup(startup.Reset)
+
+	componentThreeWorkerURI, err := cfg.ComponentThreeWorkerURI(workerName)
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	componentThree := &rpc.FakeCounter{}
+	rpc.RegisterFakeWorker(componentThreeWorkerURI, componentThree.Handle)
+	t.Cleanup(rpc.ResetFakeWorkers)
+
+	impl := &Impl{}
+	for _, value := range []uint64{3, 2} {
+		if err := impl.Add(value); err != nil {
+			t.Fatalf("%+v", err)
+		}
+	}
+
+	if actual := mustGet(t, impl); actual != 5 {
+		t.Fatalf("Expected counter: 5, actual: %d", actual)
+	}
+	if componentThree.Counter != 5 {
+		t.Fatalf("Expected component-three counter: 5, actual: %d", componentThree.Counter)
+	}
+}
+
+func TestAddCallsSingletonComponentThree(t *testing.T) {
+	t.Setenv("COMPONENT_THREE_ID", uuid.New().String())
+	t.Cleanup(startup.Reset)
+	t.Setenv("COMPONENT_THREE_ROUTING", "singleton:aggregator")
+
+	aggregatorWorkerURI, err := cfg.ComponentThreeWorkerURI("aggregator")
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	aggregator := &rpc.FakeCounter{}
+	rpc.RegisterFakeWorker(aggregatorWorkerURI, aggregator.Handle)
+	t.Cleanup(rpc.ResetFakeWorkers)
+
+	for _, workerName := range []string{"test-worker-1", "test-worker-2"} {
+		host.SetSelfWorkerName(workerName)
+		impl := &Impl{}
+		if err := impl.Add(3); err != nil {
+			t.Fatalf("%+v", err)
+		}
+	}
+
+	if aggregator.Counter != 6 {
+		t.Fatalf("Expected aggregator counter: 6, actual: %d", aggregator.Counter)
+	}
+}
+
+func TestAddReturnsErrorOfComponentThree(t *testing.T) {
+	workerName := "test-worker"
+	host.SetSelfWorkerName(workerName)
+	t.Setenv("COMPONENT_THREE_ID", uuid.New().String())
+	t.Cleanup(startup.Reset)
+
+	componentThreeWorkerURI, err := cfg.ComponentThreeWorkerURI(workerName)
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	rpc.RegisterFakeWorker(
+		componentThreeWorkerURI,
+		func(functionName string, params []any) ([]any, error) {
+			return nil, &rpc.Error{Kind: rpc.ErrorKindDenied, Message: "component-three denied"}
+		},
+	)
+	t.Cleanup(rpc.ResetFakeWorkers)
+
+	impl := &Impl{}
+	err = impl.Add(3)
+
+	if asErr := rpc.AsError(err); asErr.Kind != rpc.ErrorKindDenied ||
+		!strings.Contains(asErr.Message, "component-two: add 3 failed for test-worker") ||
+		!strings.Contains(asErr.Message, "component-three denied") {
+		t.Fatalf("Expected denied error with context, actual: %+v", err)
+	}
+	if actual := mustGet(t, impl); actual != 0 {
+		t.Fatalf("Expected counter: 0, actual: %d", actual)
+	}
+}
+
+func TestAddReturnsNotFoundErrorOfUnregisteredComponentThree(t *testing.T) {
+	host.SetSelfWorkerName("test-worker")
+	t.Setenv("COMPONENT_THREE_ID", uuid.New().String())
+	t.Cleanup(startup.Reset)
+
+	impl := &Impl{}
+	err := impl.Add(3)
+
+	if asErr := rpc.AsError(err); asErr.Kind != rpc.ErrorKindNotFound {
+		t.Fatalf("Expected not-found error, actual: %+v", err)
+	}
+	if actual := mustGet(t, impl); actual != 0 {
+		t.Fatalf("Expected counter: 0, actual: %d", actual)
+	}
+}
+
+func TestAddAndForgetCallsComponentThree(t *testing.T) {
+	workerName := "test-worker"
+	host.SetSelfWorkerName(workerName)
+	t.Setenv("COMPONENT_THREE_ID", uuid.New().String())
+	t.Cleanup(startup.Reset)
+	t.Cleanup(host.ResetLogs)
+
+	componentThreeWorkerURI, err := cfg.ComponentThreeWorkerURI(workerName)
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	componentThree := &rpc.FakeCounter{}
+	rpc.RegisterFakeWorker(componentThreeWorkerURI, componentThree.Handle)
+	t.Cleanup(rpc.ResetFakeWorkers)
+
+	impl := &Impl{}
+	if err := impl.AddAndForget(3); err != nil {
+		t.Fatalf("%+v", err)
+	}
+
+	if actual := mustGet(t, impl); actual != 3 {
+		t.Fatalf("Expected counter: 3, actual: %d", actual)
+	}
+	if componentThree.Counter != 3 {
+		t.Fatalf("Expected component-three counter: 3, actual: %d", componentThree.Counter)
+	}
+	if logs := host.Logs(); len(logs) != 0 {
+		t.Fatalf("Expected no log entries, actual: %+v", logs)
+	}
+}
+
+func TestAddAndForgetLogsDeliveryErrorOfComponentThree(t *testing.T) {
+	workerName := "test-worker"
+	host.SetSelfWorkerName(workerName)
+	t.Setenv("COMPONENT_THREE_ID", uuid.New().String())
+	t.Cleanup(startup.Reset)
+	t.Cleanup(host.ResetLogs)
+
+	componentThreeWorkerURI, err := cfg.ComponentThreeWorkerURI(workerName)
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	componentThree := &rpc.FakeCounter{}
+	rpc.RegisterFakeWorker(componentThreeWorkerURI, componentThree.Handle)
+	rpc.FailFakeDelivery(componentThreeWorkerURI, &rpc.Error{Kind: rpc.ErrorKindDenied, Message: "denied"})
+	t.Cleanup(rpc.ResetFakeWorkers)
+
+	impl := &Impl{}
+	if err := impl.AddAndForget(3); err != nil {
+		t.Fatalf("%+v", err)
+	}
+
+	if actual := mustGet(t, impl); actual != 3 {
+		t.Fatalf("Expected counter: 3, actual: %d", actual)
+	}
+	if componentThree.Counter != 0 {
+		t.Fatalf("Expected undelivered component-three counter: 0, actual: %d", componentThree.Counter)
+	}
+	expectErrorLogged(t, "denied")
+}
+
+func TestAddAndForgetLogsNotFoundErrorOfUnregisteredComponentThree(t *testing.T) {
+	host.SetSelfWorkerName("test-worker")
+	t.Setenv("COMPONENT_THREE_ID", uuid.New().String())
+	t.Cleanup(startup.Reset)
+	t.Cleanup(host.ResetLogs)
+
+	impl := &Impl{}
+	if err := impl.AddAndForget(3); err != nil {
+		t.Fatalf("%+v", err)
+	}
+
+	if actual := mustGet(t, impl); actual != 3 {
+		t.Fatalf("Expected counter: 3, actual: %d", actual)
+	}
+	expectErrorLogged(t, "no fake worker registered")
+}
+
+func TestAddWithInvalidConfigReturnsConfigError(t *testing.T) {
+	t.Setenv("COMPONENT_THREE_ID", "")
+	t.Setenv("COMPONENT_THREE_ROUTING", "sharded:0")
+	t.Cleanup(startup.Reset)
+	t.Cleanup(host.ResetLogs)
+
+	impl := &Impl{}
+	err := impl.Add(3)
+
+	if asErr := rpc.AsError(err); asErr.Kind != rpc.ErrorKindConfigError ||
+		!strings.Contains(asErr.Message, "COMPONENT_THREE_ID (component-three): missing required value") ||
+		!strings.Contains(asErr.Message, "COMPONENT_THREE_ROUTING (component-three): sharded routing") {
+		t.Fatalf("Expected config-error listing all invalid values, actual: %+v", err)
+	}
+	if _, err := impl.Get(); rpc.AsError(err).Kind != rpc.ErrorKindConfigError ||
+		!strings.HasPrefix(rpc.AsError(err).Message, "component-two: get failed") {
+		t.Fatalf("Expected config-error from get, actual: %+v", err)
+	}
+	if logs := host.Logs(); len(logs) != 0 {
+		t.Fatalf("Expected no log entries, actual: %+v", logs)
+	}
+
+	// the result of the first validation is kept
+	t.Setenv("COMPONENT_THREE_ID", uuid.New().String())
+	t.Setenv("COMPONENT_THREE_ROUTING", "")
+	if err := impl.Add(3); rpc.AsError(err).Kind != rpc.ErrorKindConfigError {
+		t.Fatalf("Expected config-error, actual: %+v", err)
+	}
+	if err := impl.AddAndForget(3); rpc.AsError(err).Kind != rpc.ErrorKindConfigError {
+		t.Fatalf("Expected config-error from add-and-forget, actual: %+v", err)
+	}
+}
+
+func mustGet(t *testing.T, impl *Impl) uint64 {
+	t.Helper()
+	counter, err := impl.Get()
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	return counter
+}
+
+func expectErrorLogged(t *testing.T, expected string) {
+	t.Helper()
+	logs := host.Logs()
+	if len(logs) != 1 {
+		t.Fatalf("Expected 1 log entry, actual: %+v", logs)
+	}
+	if logs[0].Level != host.LogLevelError || logs[0].Context != "componentthree" ||
+		!strings.Contains(logs[0].Message, expected) {
+		t.Fatalf("Expected componentthree error log containing %q, actual: %+v", expected, logs[0])
+	}
+}
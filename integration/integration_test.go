@@ -2,12 +2,16 @@ package integration
 
 import (
 	"fmt"
-	"strings"
 	"testing"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/magefile/mage/sh"
 	"github.com/tidwall/gjson"
+
+	"golem-go-project/lib/cfg/components"
+	"golem-go-project/tools/wave"
+	"golem-go-project/tools/workerenv"
 )
 
 func TestDeployed(t *testing.T) {
@@ -19,6 +23,9 @@ func TestDeployed(t *testing.T) {
 
 	componentThreeURN := mustGetComponentURNByComponentName(t, "component-three")
 	fmt.Printf("component-three: %s\n", componentThreeURN)
+
+	componentFourURN := mustGetComponentURNByComponentName(t, "component-four")
+	fmt.Printf("component-four: %s\n", componentFourURN)
 }
 
 func TestCallingAddOnComponentOneCallsToOtherComponents(t *testing.T) {
@@ -45,7 +52,8 @@ func TestCallingAddOnComponentOneCallsToOtherComponents(t *testing.T) {
 
 	// Invoke add on component-one
 	{
-		mustInvokeAndAwaitComponent(t, "component-one", workerName, "golem:component-one/component-one-api.{add}", []string{"3"})
+		output := mustInvokeAndAwaitComponent(t, "component-one", workerName, "golem:component-one/component-one-api.{add}", uint64(3))
+		expectResultOk(t, output)
 	}
 
 	// Call get on all component and check the counters are accumulated on component two and three
@@ -57,7 +65,8 @@ func TestCallingAddOnComponentOneCallsToOtherComponents(t *testing.T) {
 
 	// Invoke add on component-two
 	{
-		mustInvokeAndAwaitComponent(t, "component-two", workerName, "golem:component-two/component-two-api.{add}", []string{"2"})
+		output := mustInvokeAndAwaitComponent(t, "component-two", workerName, "golem:component-two/component-two-api.{add}", uint64(2))
+		expectResultOk(t, output)
 	}
 
 	// Call get on all component and check the counters are accumulated on component three
@@ -69,7 +78,8 @@ func TestCallingAddOnComponentOneCallsToOtherComponents(t *testing.T) {
 
 	// Invoke add on component-one again
 	{
-		mustInvokeAndAwaitComponent(t, "component-one", workerName, "golem:component-one/component-one-api.{add}", []string{"1"})
+		output := mustInvokeAndAwaitComponent(t, "component-one", workerName, "golem:component-one/component-one-api.{add}", uint64(1))
+		expectResultOk(t, output)
 	}
 
 	// Call get on all component and check the counters are accumulated on component two and three
@@ -80,6 +90,75 @@ func TestCallingAddOnComponentOneCallsToOtherComponents(t *testing.T) {
 	}
 }
 
+func TestCallingAddAndForgetOnComponentTwoUpdatesComponentThreeEventually(t *testing.T) {
+	workerName := uuid.New().String()
+	fmt.Printf("random worker name for test: %s\n", workerName)
+
+	mustAddComponent(t, "component-two", workerName, mustGetComponentURNs(t))
+
+	for _, value := range []uint64{3, 2} {
+		output := mustInvokeAndAwaitComponent(t, "component-two", workerName, "golem:component-two/component-two-api.{add-and-forget}", value)
+		expectResultOk(t, output)
+	}
+
+	// component-two calls component-three in fire-and-forget mode, so its counter is eventually consistent
+	expectCounter(t, "component-two", workerName, 5)
+	expectCounterEventually(t, "component-three", workerName, 5)
+}
+
+func TestCallingAddOnComponentTwoWithSingletonRoutingAggregatesOnComponentThree(t *testing.T) {
+	aggregatorName := uuid.New().String()
+	workerNames := []string{uuid.New().String(), uuid.New().String()}
+	fmt.Printf("random worker names for test: %s, %+v\n", aggregatorName, workerNames)
+
+	componentURNs := mustGetComponentURNs(t)
+	for _, workerName := range workerNames {
+		mustAddComponent(t, "component-two", workerName, componentURNs, fmt.Sprintf("COMPONENT_THREE_ROUTING=singleton:%s", aggregatorName))
+	}
+
+	for i, workerName := range workerNames {
+		output := mustInvokeAndAwaitComponent(t, "component-two", workerName, "golem:component-two/component-two-api.{add}", uint64(i+1))
+		expectResultOk(t, output)
+	}
+
+	expectCounter(t, "component-two", workerNames[0], 1)
+	expectCounter(t, "component-two", workerNames[1], 2)
+	expectCounter(t, "component-three", aggregatorName, 3)
+}
+
+func TestCallingAddWithoutComponentIDsReturnsConfigError(t *testing.T) {
+	// workers created on the first invocation have no component ID env vars
+	workerName := uuid.New().String()
+	fmt.Printf("random worker name for test: %s\n", workerName)
+
+	output := mustInvokeAndAwaitComponent(t, "component-one", workerName, "golem:component-one/component-one-api.{add}", uint64(3))
+	expectResultErr(t, output, "config-error")
+
+	output = mustInvokeAndAwaitComponent(t, "component-one", workerName, "golem:component-one/component-one-api.{get}")
+	var result wave.Result[uint64, wave.Variant]
+	err := wave.DecodeResults(output, &result)
+	if err != nil {
+		t.Fatalf("Expected result, %+v", err)
+	}
+	if !result.IsErr || result.Err.Case != "config-error" {
+		t.Fatalf("Expected config-error error result from get, actual: %s", output)
+	}
+}
+
+func TestCallingAddOnComponentFourStoresCountersInKeyValue(t *testing.T) {
+	workerName := uuid.New().String()
+	fmt.Printf("random worker name for test: %s\n", workerName)
+
+	expectKeyCounter(t, workerName, "a", 0)
+
+	mustAddKeyCounter(t, workerName, "a", 3)
+	mustAddKeyCounter(t, workerName, "a", 2)
+	mustAddKeyCounter(t, workerName, "b", 1)
+
+	expectKeyCounter(t, workerName, "a", 5)
+	expectKeyCounter(t, workerName, "b", 1)
+}
+
 func getComponentURNByComponentName(componentName string) (string, error) {
 	output, err := sh.Output(
 		"golem-cli", "--format", "json", "component", "get", "--component-name", componentName,
@@ -104,47 +183,58 @@ func mustGetComponentURNByComponentName(t *testing.T, componentName string) stri
 	return name
 }
 
-type ComponentURNs struct {
-	ComponentOne   string
-	ComponentTwo   string
-	ComponentThree string
-}
-
-func mustGetComponentURNs(t *testing.T) ComponentURNs {
-	return ComponentURNs{
-		ComponentOne:   mustGetComponentURNByComponentName(t, "component-one"),
-		ComponentTwo:   mustGetComponentURNByComponentName(t, "component-two"),
-		ComponentThree: mustGetComponentURNByComponentName(t, "component-three"),
+// mustGetComponentURNs returns the URNs of the deployed components by component name
+func mustGetComponentURNs(t *testing.T) map[string]string {
+	componentURNs := map[string]string{}
+	for _, componentName := range []string{"component-one", "component-two", "component-three"} {
+		componentURNs[componentName] = mustGetComponentURNByComponentName(t, componentName)
 	}
+	return componentURNs
 }
 
-func addComponent(componentName, workerName string, componentURNs ComponentURNs) error {
+// addComponent adds the worker with the component ID env vars needed for its dependencies, and the extra env vars in
+// NAME=value form
+func addComponent(componentName, workerName string, componentURNs map[string]string, extraEnv ...string) error {
 	fmt.Printf("adding component: %s, %s\n", componentName, workerName)
-	output, err := sh.Output(
-		"golem-cli", "worker",
+	env, err := workerenv.Compute(workerenv.Graph(components.Dependencies), componentURNs, componentName)
+	if err != nil {
+		return fmt.Errorf("addComponent for %s, %s: %w", componentName, workerName, err)
+	}
+
+	args := []string{
+		"worker",
 		"--format", "json",
 		"add",
 		"--component-name", componentName,
 		"--worker-name", workerName,
-		"--env", fmt.Sprintf("COMPONENT_ONE_ID=%s", componentIDFromURN(componentURNs.ComponentOne)),
-		"--env", fmt.Sprintf("COMPONENT_TWO_ID=%s", componentIDFromURN(componentURNs.ComponentTwo)),
-		"--env", fmt.Sprintf("COMPONENT_THREE_ID=%s", componentIDFromURN(componentURNs.ComponentThree)),
-	)
+	}
+	args = append(args, env.CLIArgs()...)
+	for _, extra := range extraEnv {
+		args = append(args, "--env", extra)
+	}
+	output, err := sh.Output("golem-cli", args...)
 	if err != nil {
 		return fmt.Errorf("addComponent for %s, %s: golem-cli failed: %w\n%s", componentName, workerName, err, output)
 	}
 	return nil
 }
 
-func mustAddComponent(t *testing.T, componentName, workerName string, componentURNs ComponentURNs) {
-	err := addComponent(componentName, workerName, componentURNs)
+func mustAddComponent(t *testing.T, componentName, workerName string, componentURNs map[string]string, extraEnv ...string) {
+	err := addComponent(componentName, workerName, componentURNs, extraEnv...)
 	if err != nil {
 		t.Fatalf("%+v", err)
 	}
 }
 
-func invokeAndAwaitComponent(componentName, workerName, function string, functionArgs []string) (string, error) {
-	fmt.Printf("invoking component: %s, %s, %s, %+v\n", componentName, workerName, function, functionArgs)
+// invokeAndAwaitComponent invokes the function with the args encoded as WAVE, see the wave package for the supported
+// types
+func invokeAndAwaitComponent(componentName, workerName, function string, functionArgs ...any) (string, error) {
+	encodedArgs, err := wave.EncodeArgs(functionArgs...)
+	if err != nil {
+		return "", fmt.Errorf("invokeAndAwaitComponent failed: %w", err)
+	}
+
+	fmt.Printf("invoking component: %s, %s, %s, %+v\n", componentName, workerName, function, encodedArgs)
 
 	cliArgs := []string{
 		"--format", "json",
@@ -155,7 +245,7 @@ func invokeAndAwaitComponent(componentName, workerName, function string, functio
 		"--function", function,
 	}
 
-	for _, arg := range functionArgs {
+	for _, arg := range encodedArgs {
 		cliArgs = append(cliArgs, []string{"--arg", arg}...)
 	}
 
@@ -169,33 +259,113 @@ func invokeAndAwaitComponent(componentName, workerName, function string, functio
 	return output, nil
 }
 
-func mustInvokeAndAwaitComponent(t *testing.T, componentURN, workerName, function string, functionArgs []string) string {
-	output, err := invokeAndAwaitComponent(componentURN, workerName, function, functionArgs)
+func mustInvokeAndAwaitComponent(t *testing.T, componentURN, workerName, function string, functionArgs ...any) string {
+	output, err := invokeAndAwaitComponent(componentURN, workerName, function, functionArgs...)
 	if err != nil {
 		t.Fatalf("%+v", err)
 	}
 	return output
 }
 
-func componentIDFromURN(urn string) string {
-	return strings.Split(urn, ":")[2]
+func expectCounter(t *testing.T, componentName, workerName string, expected uint64) {
+	actual := mustGetCounter(t, componentName, workerName, expected)
+	if expected != actual {
+		t.Fatalf("Expected counter for %s, %s: %d, actual: %d", componentName, workerName, expected, actual)
+	}
+}
+
+// expectCounterEventually polls the counter until it reaches the expected value, for counters updated by
+// fire-and-forget invocations
+func expectCounterEventually(t *testing.T, componentName, workerName string, expected uint64) {
+	deadline := time.Now().Add(counterTimeout)
+	for {
+		actual := mustGetCounter(t, componentName, workerName, expected)
+		if expected == actual {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("Expected counter for %s, %s: %d within %s, actual: %d", componentName, workerName, expected, counterTimeout, actual)
+		}
+		time.Sleep(counterPollInterval)
+	}
 }
 
-func expectCounter(t *testing.T, componentName, workerName string, expected int64) {
-	output := mustInvokeAndAwaitComponent(t, componentName, workerName, fmt.Sprintf("golem:%s/%s-api.{get}", componentName, componentName), nil)
+const (
+	counterTimeout      = 30 * time.Second
+	counterPollInterval = 500 * time.Millisecond
+)
 
-	actualValue := gjson.Get(output, "value")
-	if !actualValue.Exists() {
-		t.Fatalf("Expected counter for %s, %s: %d, actual value is missing", componentName, workerName, expected)
+func mustGetCounter(t *testing.T, componentName, workerName string, expected uint64) uint64 {
+	output := mustInvokeAndAwaitComponent(t, componentName, workerName, fmt.Sprintf("golem:%s/%s-api.{get}", componentName, componentName))
+
+	// component-three returns the bare counter, the other components validate their config and return a result
+	if componentName == "component-three" {
+		var actual uint64
+		err := wave.DecodeResults(output, &actual)
+		if err != nil {
+			t.Fatalf("Expected counter for %s, %s: %d, %+v", componentName, workerName, expected, err)
+		}
+		return actual
 	}
 
-	actualArray := actualValue.Array()
-	if len(actualArray) != 1 {
-		t.Fatalf("Expected counter for %s, %s: %d, actual value tuple has bad number of elements: %s", componentName, workerName, expected, actualValue)
+	var result wave.Result[uint64, wave.Variant]
+	err := wave.DecodeResults(output, &result)
+	if err != nil {
+		t.Fatalf("Expected counter for %s, %s: %d, %+v", componentName, workerName, expected, err)
 	}
+	if result.IsErr {
+		t.Fatalf("Expected counter for %s, %s: %d, actual error: %s", componentName, workerName, expected, output)
+	}
+	return result.Ok
+}
 
-	actual := actualArray[0].Int()
-	if expected != actual {
-		t.Fatalf("Expected counter for %s, %s: %d, actual: %d", componentName, workerName, expected, actual)
+func mustAddKeyCounter(t *testing.T, workerName, key string, value uint64) {
+	output := mustInvokeAndAwaitComponent(t, "component-four", workerName, "golem:component-four/component-four-api.{add}", key, value)
+
+	var result wave.Result[struct{}, string]
+	err := wave.DecodeResults(output, &result)
+	if err != nil {
+		t.Fatalf("Expected result, %+v", err)
+	}
+	if result.IsErr {
+		t.Fatalf("Expected ok result for %s, %s, actual error: %s", workerName, key, result.Err)
+	}
+}
+
+func expectKeyCounter(t *testing.T, workerName, key string, expected uint64) {
+	output := mustInvokeAndAwaitComponent(t, "component-four", workerName, "golem:component-four/component-four-api.{get}", key)
+
+	var result wave.Result[uint64, string]
+	err := wave.DecodeResults(output, &result)
+	if err != nil {
+		t.Fatalf("Expected counter for %s, %s: %d, %+v", workerName, key, expected, err)
+	}
+	if result.IsErr {
+		t.Fatalf("Expected counter for %s, %s: %d, actual error: %s", workerName, key, expected, result.Err)
+	}
+	if expected != result.Ok {
+		t.Fatalf("Expected counter for %s, %s: %d, actual: %d", workerName, key, expected, result.Ok)
+	}
+}
+
+// mustDecodeResult decodes the result<_, error> returned by the function
+func mustDecodeResult(t *testing.T, output string) wave.Result[struct{}, wave.Variant] {
+	var result wave.Result[struct{}, wave.Variant]
+	err := wave.DecodeResults(output, &result)
+	if err != nil {
+		t.Fatalf("Expected result, %+v", err)
+	}
+	return result
+}
+
+func expectResultOk(t *testing.T, output string) {
+	if result := mustDecodeResult(t, output); result.IsErr {
+		t.Fatalf("Expected ok result, actual: %s", output)
+	}
+}
+
+func expectResultErr(t *testing.T, output, errorCase string) {
+	if result := mustDecodeResult(t, output); !result.IsErr || result.Err.Case != errorCase {
+		t.Fatalf("Expected %s error result, actual: %s", errorCase, output)
 	}
 }
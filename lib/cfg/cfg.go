@@ -2,66 +2,81 @@ package cfg
 
 import (
 	"fmt"
-	"os"
+	"strconv"
 	"strings"
 
 	"github.com/google/uuid"
-
-	"github.com/golemcloud/golem-go/binding"
-	"github.com/golemcloud/golem-go/golemhost"
 )
 
-func ComponentIDFromEnv(key string) (golemhost.ComponentID, error) {
-	value := os.Getenv(key)
-	if value == "" {
-		return [16]byte{}, fmt.Errorf("missing environment variable for component id: %s", key)
-	}
-	componentID, err := uuid.Parse(strings.ToLower(value))
-	if err != nil {
-		return [16]byte{}, fmt.Errorf("component id parse failed for %s=%s, %w", key, value, err)
-	}
-	return golemhost.ComponentID(componentID), nil
-}
-
-func ComponentOneID() (golemhost.ComponentID, error) {
-	return ComponentIDFromEnv("COMPONENT_ONE_ID")
+// Error is a configuration error, e.g. a missing or invalid environment variable
+type Error struct {
+	Message string
+	Err     error
 }
 
-func ComponentTwoID() (golemhost.ComponentID, error) {
-	return ComponentIDFromEnv("COMPONENT_TWO_ID")
+func (e *Error) Error() string {
+	if e.Err != nil {
+		return fmt.Sprintf("%s, %s", e.Message, e.Err)
+	}
+	return e.Message
 }
 
-func ComponentThreeID() (golemhost.ComponentID, error) {
-	return ComponentIDFromEnv("COMPONENT_THREE_ID")
+func (e *Error) Unwrap() error {
+	return e.Err
 }
 
-func ComponentOneWorkerURI(workerName string) (binding.GolemRpc0_1_0_TypesUri, error) {
-	uri, err := workerURIF(ComponentOneID, workerName)
-	return uri, err
+// ComponentIDFromEnv returns the component id from the env var or the config file (see Lookup), see ParseComponentID for the accepted formats, and
+// the generated accessors, e.g. ComponentOneID
+func ComponentIDFromEnv(key string) (ComponentID, error) {
+	value, _ := Lookup(key)
+	if value == "" {
+		return ComponentID{}, &Error{Message: fmt.Sprintf("missing environment variable for component id: %s", key)}
+	}
+	componentID, err := ParseComponentID(value)
+	if err != nil {
+		return ComponentID{}, &Error{Message: fmt.Sprintf("component id parse failed for %s=%s", key, value), Err: err}
+	}
+	return componentID, nil
 }
 
-func ComponentTwoWorkerURI(workerName string) (binding.GolemRpc0_1_0_TypesUri, error) {
-	uri, err := workerURIF(ComponentTwoID, workerName)
-	return uri, err
-}
+// componentURNPrefix is the prefix of the component URNs printed by golem-cli
+const componentURNPrefix = "urn:component:"
 
-func ComponentThreeWorkerURI(workerName string) (binding.GolemRpc0_1_0_TypesUri, error) {
-	uri, err := workerURIF(ComponentThreeID, workerName)
-	return uri, err
-}
+// ParseComponentID parses a component id from a bare UUID, a component URN (urn:component:<uuid>) or a versioned
+// component URN (urn:component:<uuid>#<version>), as printed by golem-cli. The version of versioned URNs is ignored,
+// as workers are addressed by component id only.
+func ParseComponentID(value string) (ComponentID, error) {
+	id := strings.TrimSpace(value)
+	if len(id) >= len(componentURNPrefix) && strings.EqualFold(id[:len(componentURNPrefix)], componentURNPrefix) {
+		id = id[len(componentURNPrefix):]
+		if uuidPart, version, ok := strings.Cut(id, "#"); ok {
+			if _, err := strconv.ParseUint(version, 10, 64); err != nil {
+				return ComponentID{}, fmt.Errorf("invalid component version in URN %q: %q", value, version)
+			}
+			id = uuidPart
+		}
+	} else if strings.HasPrefix(strings.ToLower(id), "urn:") {
+		return ComponentID{}, fmt.Errorf("expected component URN with %s prefix, got %q", componentURNPrefix, value)
+	}
 
-func WorkerURI[T binding.GolemRpc0_1_0_TypesUri](workerID golemhost.WorkerID) T {
-	return T(binding.GolemRpc0_1_0_TypesUri{
-		Value: fmt.Sprintf("urn:worker:%s/%s", (uuid.UUID(workerID.ComponentID)).URN(), workerID.WorkerName),
-	})
+	componentID, err := uuid.Parse(strings.ToLower(id))
+	if err != nil {
+		return ComponentID{}, fmt.Errorf("expected UUID (e.g. 4bcc2fb0-31c5-4fb1-9e6d-4f2ae0e5c8e6), got %q, %w", id, err)
+	}
+	return ComponentID(componentID), nil
 }
 
-func workerURIF(getComponentID func() (golemhost.ComponentID, error), workerName string) (binding.GolemRpc0_1_0_TypesUri, error) {
+// workerURIF returns the URI of the worker of the component, after validating the worker name
+func workerURIF(getComponentID func() (ComponentID, error), workerName string) (URI, error) {
 	componentID, err := getComponentID()
 	if err != nil {
-		return binding.GolemRpc0_1_0_TypesUri{}, err
+		return URI{}, err
+	}
+	err = ValidateWorkerName(workerName)
+	if err != nil {
+		return URI{}, err
 	}
-	return WorkerURI(golemhost.WorkerID{
+	return WorkerURI(WorkerID{
 		ComponentID: componentID,
 		WorkerName:  workerName,
 	}), nil
@@ -0,0 +1,68 @@
+//go:build hosttest
+
+package cfg
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestParseComponentID(t *testing.T) {
+	expected := ComponentID(uuid.MustParse("4bcc2fb0-31c5-4fb1-9e6d-4f2ae0e5c8e6"))
+	values := []string{
+		"4bcc2fb0-31c5-4fb1-9e6d-4f2ae0e5c8e6",
+		"4BCC2FB0-31C5-4FB1-9E6D-4F2AE0E5C8E6",
+		" 4bcc2fb0-31c5-4fb1-9e6d-4f2ae0e5c8e6\n",
+		"urn:component:4bcc2fb0-31c5-4fb1-9e6d-4f2ae0e5c8e6",
+		"URN:Component:4bcc2fb0-31c5-4fb1-9e6d-4f2ae0e5c8e6",
+		"urn:component:4bcc2fb0-31c5-4fb1-9e6d-4f2ae0e5c8e6#3",
+	}
+	for _, value := range values {
+		actual, err := ParseComponentID(value)
+		if err != nil {
+			t.Fatalf("%+v", err)
+		}
+		if actual != expected {
+			t.Fatalf("Expected component id for %q: %s, actual: %s", value, uuid.UUID(expected), uuid.UUID(actual))
+		}
+	}
+}
+
+func TestParseComponentIDErrors(t *testing.T) {
+	tests := map[string]string{
+		"component-one":  `expected UUID (e.g. 4bcc2fb0-31c5-4fb1-9e6d-4f2ae0e5c8e6), got "component-one"`,
+		"urn:component:": `expected UUID (e.g. 4bcc2fb0-31c5-4fb1-9e6d-4f2ae0e5c8e6), got ""`,
+		"urn:component:4bcc2fb0-31c5-4fb1-9e6d-4f2ae0e5c8e6#latest": `invalid component version in URN`,
+		"urn:worker:4bcc2fb0-31c5-4fb1-9e6d-4f2ae0e5c8e6/w":         `expected component URN with urn:component: prefix`,
+		"urn:uuid:4bcc2fb0-31c5-4fb1-9e6d-4f2ae0e5c8e6":             `expected component URN with urn:component: prefix`,
+	}
+	for value, expected := range tests {
+		_, err := ParseComponentID(value)
+		if err == nil || !strings.Contains(err.Error(), expected) {
+			t.Fatalf("Expected error for %q containing %q, actual: %v", value, expected, err)
+		}
+	}
+}
+
+func TestComponentIDFromEnv(t *testing.T) {
+	t.Setenv("COMPONENT_ONE_ID", "urn:component:4bcc2fb0-31c5-4fb1-9e6d-4f2ae0e5c8e6#1")
+	if _, err := ComponentOneID(); err != nil {
+		t.Fatalf("%+v", err)
+	}
+
+	t.Setenv("COMPONENT_ONE_ID", "")
+	_, err := ComponentOneID()
+	var cfgErr *Error
+	if !errors.As(err, &cfgErr) || cfgErr.Message != "missing environment variable for component id: COMPONENT_ONE_ID" {
+		t.Fatalf("Expected missing env var error, actual: %+v", err)
+	}
+
+	t.Setenv("COMPONENT_ONE_ID", "urn:component:x")
+	_, err = ComponentOneID()
+	if !errors.As(err, &cfgErr) || !strings.HasPrefix(err.Error(), "component id parse failed for COMPONENT_ONE_ID=urn:component:x, expected UUID") {
+		t.Fatalf("Expected parse error, actual: %+v", err)
+	}
+}
@@ -0,0 +1,43 @@
+// Code generated by the generateCfgComponents magefile command. DO NOT EDIT.
+
+// Package components contains the env var names and the worker to worker RPC dependencies of the components. It
+// does not depend on the golem bindings, so it can also be used on the host, e.g. by the tools and integration tests.
+package components
+
+// The env vars of the component ids, the workers calling a component need its id in the env var
+const (
+	ComponentFourIDEnvVar  = "COMPONENT_FOUR_ID"
+	ComponentOneIDEnvVar   = "COMPONENT_ONE_ID"
+	ComponentThreeIDEnvVar = "COMPONENT_THREE_ID"
+	ComponentTwoIDEnvVar   = "COMPONENT_TWO_ID"
+)
+
+// The env vars of the routings of the components as dependencies, see cfg.ComponentRouting
+const (
+	ComponentFourRoutingEnvVar  = "COMPONENT_FOUR_ROUTING"
+	ComponentOneRoutingEnvVar   = "COMPONENT_ONE_ROUTING"
+	ComponentThreeRoutingEnvVar = "COMPONENT_THREE_ROUTING"
+	ComponentTwoRoutingEnvVar   = "COMPONENT_TWO_ROUTING"
+)
+
+// IDEnvVars are the env vars of the component ids by component name
+var IDEnvVars = map[string]string{
+	"component-four":  ComponentFourIDEnvVar,
+	"component-one":   ComponentOneIDEnvVar,
+	"component-three": ComponentThreeIDEnvVar,
+	"component-two":   ComponentTwoIDEnvVar,
+}
+
+// RoutingEnvVars are the env vars of the routings by component name
+var RoutingEnvVars = map[string]string{
+	"component-four":  ComponentFourRoutingEnvVar,
+	"component-one":   ComponentOneRoutingEnvVar,
+	"component-three": ComponentThreeRoutingEnvVar,
+	"component-two":   ComponentTwoRoutingEnvVar,
+}
+
+// Dependencies are the worker to worker RPC dependencies by component name
+var Dependencies = map[string][]string{
+	"component-one": {"component-two", "component-three"},
+	"component-two": {"component-three"},
+}
@@ -0,0 +1,90 @@
+// Code generated by the generateCfgComponents magefile command. DO NOT EDIT.
+
+package cfg
+
+import "golem-go-project/lib/cfg/components"
+
+// The env vars of the component ids, see components.IDEnvVars
+const (
+	ComponentFourIDEnvVar  = components.ComponentFourIDEnvVar
+	ComponentOneIDEnvVar   = components.ComponentOneIDEnvVar
+	ComponentThreeIDEnvVar = components.ComponentThreeIDEnvVar
+	ComponentTwoIDEnvVar   = components.ComponentTwoIDEnvVar
+)
+
+// The env vars of the routings of the components as dependencies, see ComponentRouting
+const (
+	ComponentFourRoutingEnvVar  = components.ComponentFourRoutingEnvVar
+	ComponentOneRoutingEnvVar   = components.ComponentOneRoutingEnvVar
+	ComponentThreeRoutingEnvVar = components.ComponentThreeRoutingEnvVar
+	ComponentTwoRoutingEnvVar   = components.ComponentTwoRoutingEnvVar
+)
+
+// ComponentIDEnvVars are the env vars of the component ids by component name
+var ComponentIDEnvVars = components.IDEnvVars
+
+// ComponentRoutingEnvVars are the env vars of the routings by component name
+var ComponentRoutingEnvVars = components.RoutingEnvVars
+
+// ComponentDependencies are the worker to worker RPC dependencies by component name
+var ComponentDependencies = components.Dependencies
+
+// ComponentFourID returns the id of component-four from the COMPONENT_FOUR_ID env var
+func ComponentFourID() (ComponentID, error) {
+	return ComponentIDFromEnv(ComponentFourIDEnvVar)
+}
+
+// ComponentFourWorkerURI returns the URI of the component-four worker with the name
+func ComponentFourWorkerURI(workerName string) (URI, error) {
+	return workerURIF(ComponentFourID, workerName)
+}
+
+// ComponentFourRoutedWorkerURI returns the URI of the component-four worker routed for the key, see ComponentRouting
+func ComponentFourRoutedWorkerURI(key string) (URI, error) {
+	return routedWorkerURIF("component-four", ComponentFourRoutingEnvVar, ComponentFourID, key)
+}
+
+// ComponentOneID returns the id of component-one from the COMPONENT_ONE_ID env var
+func ComponentOneID() (ComponentID, error) {
+	return ComponentIDFromEnv(ComponentOneIDEnvVar)
+}
+
+// ComponentOneWorkerURI returns the URI of the component-one worker with the name
+func ComponentOneWorkerURI(workerName string) (URI, error) {
+	return workerURIF(ComponentOneID, workerName)
+}
+
+// ComponentOneRoutedWorkerURI returns the URI of the component-one worker routed for the key, see ComponentRouting
+func ComponentOneRoutedWorkerURI(key string) (URI, error) {
+	return routedWorkerURIF("component-one", ComponentOneRoutingEnvVar, ComponentOneID, key)
+}
+
+// ComponentThreeID returns the id of component-three from the COMPONENT_THREE_ID env var
+func ComponentThreeID() (ComponentID, error) {
+	return ComponentIDFromEnv(ComponentThreeIDEnvVar)
+}
+
+// ComponentThreeWorkerURI returns the URI of the component-three worker with the name
+func ComponentThreeWorkerURI(workerName string) (URI, error) {
+	return workerURIF(ComponentThreeID, workerName)
+}
+
+// ComponentThreeRoutedWorkerURI returns the URI of the component-three worker routed for the key, see ComponentRouting
+func ComponentThreeRoutedWorkerURI(key string) (URI, error) {
+	return routedWorkerURIF("component-three", ComponentThreeRoutingEnvVar, ComponentThreeID, key)
+}
+
+// ComponentTwoID returns the id of component-two from the COMPONENT_TWO_ID env var
+func ComponentTwoID() (ComponentID, error) {
+	return ComponentIDFromEnv(ComponentTwoIDEnvVar)
+}
+
+// ComponentTwoWorkerURI returns the URI of the component-two worker with the name
+func ComponentTwoWorkerURI(workerName string) (URI, error) {
+	return workerURIF(ComponentTwoID, workerName)
+}
+
+// ComponentTwoRoutedWorkerURI returns the URI of the component-two worker routed for the key, see ComponentRouting
+func ComponentTwoRoutedWorkerURI(key string) (URI, error) {
+	return routedWorkerURIF("component-two", ComponentTwoRoutingEnvVar, ComponentTwoID, key)
+}
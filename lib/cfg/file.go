@@ -0,0 +1,182 @@
+package cfg
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+
+	"golem-go-project/lib/host"
+)
+
+// ConfigFileEnvVar is the env var of the path of the optional config file, in a preopened directory of the worker
+const ConfigFileEnvVar = "CONFIG_FILE"
+
+var (
+	fileValues   = map[string]string{}
+	fileLoaded   bool
+	fileLoadErr  error
+	fileValuesMu sync.Mutex
+)
+
+// LoadConfigFile loads the config file from the path in the CONFIG_FILE env var, if it is set. The file is only loaded
+// once, later calls return the result of the first one. See LoadFile for the format.
+func LoadConfigFile() error {
+	fileValuesMu.Lock()
+	defer fileValuesMu.Unlock()
+	if fileLoaded {
+		return fileLoadErr
+	}
+	fileLoaded = true
+
+	filePath := os.Getenv(ConfigFileEnvVar)
+	if filePath == "" {
+		return nil
+	}
+	values, err := readConfigFile(filePath)
+	if err != nil {
+		fileLoadErr = &Error{Message: fmt.Sprintf("config file load failed for %s=%s", ConfigFileEnvVar, filePath), Err: err}
+		return fileLoadErr
+	}
+	fileValues = values
+	return nil
+}
+
+// LoadFile loads the JSON (.json) or TOML (.toml) config file with the path from the preopened directories of the
+// worker, replacing the values of previously loaded files. The keys of the file are mapped to env var names, so the
+// values are used as defaults of the env vars (see Lookup):
+//   - keys are upper-cased, and - and . are replaced with _, e.g. component-three-id is COMPONENT_THREE_ID
+//   - nested keys are joined with _, e.g. timeout in the [rpc] table is RPC_TIMEOUT
+//   - arrays are joined with , e.g. ["a", "b"] is "a,b"
+func LoadFile(filePath string) error {
+	values, err := readConfigFile(filePath)
+	if err != nil {
+		return &Error{Message: fmt.Sprintf("config file load failed for %s", filePath), Err: err}
+	}
+
+	fileValuesMu.Lock()
+	defer fileValuesMu.Unlock()
+	fileValues = values
+	fileLoaded, fileLoadErr = true, nil
+	return nil
+}
+
+// ResetFile removes the loaded config file values, so the next LoadConfigFile call loads the file again
+func ResetFile() {
+	fileValuesMu.Lock()
+	defer fileValuesMu.Unlock()
+	fileValues = map[string]string{}
+	fileLoaded, fileLoadErr = false, nil
+}
+
+// Lookup returns the value of the env var, or the value of the key in the loaded config file if the env var is
+// missing or empty, so env vars override the config file
+func Lookup(key string) (string, bool) {
+	if value := os.Getenv(key); value != "" {
+		return value, true
+	}
+
+	fileValuesMu.Lock()
+	defer fileValuesMu.Unlock()
+	value, ok := fileValues[key]
+	return value, ok
+}
+
+func readConfigFile(filePath string) (map[string]string, error) {
+	data, err := host.ReadFile(filePath)
+	if err != nil {
+		return nil, err
+	}
+
+	var document map[string]any
+	switch ext := strings.ToLower(path.Ext(filePath)); ext {
+	case ".json":
+		decoder := json.NewDecoder(bytes.NewReader(data))
+		decoder.UseNumber()
+		err = decoder.Decode(&document)
+		if err != nil {
+			return nil, fmt.Errorf("json parse failed, %w", err)
+		}
+	case ".toml":
+		document, err = parseTOML(string(data))
+		if err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("expected .json or .toml config file, got %q", ext)
+	}
+
+	values := map[string]string{}
+	err = flattenConfig(values, "", document)
+	if err != nil {
+		return nil, err
+	}
+	return values, nil
+}
+
+// flattenConfig adds the values of the document to values, keyed by env var names
+func flattenConfig(values map[string]string, prefix string, document map[string]any) error {
+	keys := make([]string, 0, len(document))
+	for key := range document {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		envVar := strings.ToUpper(strings.NewReplacer("-", "_", ".", "_").Replace(key))
+		if prefix != "" {
+			envVar = prefix + "_" + envVar
+		}
+
+		if table, ok := document[key].(map[string]any); ok {
+			err := flattenConfig(values, envVar, table)
+			if err != nil {
+				return err
+			}
+			continue
+		}
+
+		value, err := configValueString(document[key])
+		if err != nil {
+			return fmt.Errorf("key %s: %w", envVar, err)
+		}
+		if _, ok := values[envVar]; ok {
+			return fmt.Errorf("key %s: duplicate key", envVar)
+		}
+		values[envVar] = value
+	}
+	return nil
+}
+
+func configValueString(value any) (string, error) {
+	switch v := value.(type) {
+	case nil:
+		return "", nil
+	case string:
+		return v, nil
+	case bool, int64, float64, json.Number:
+		return fmt.Sprint(v), nil
+	case []any:
+		items := make([]string, len(v))
+		for i, item := range v {
+			if _, ok := item.([]any); ok {
+				return "", fmt.Errorf("nested arrays are not supported")
+			}
+			if _, ok := item.(map[string]any); ok {
+				return "", fmt.Errorf("arrays of tables are not supported")
+			}
+			s, err := configValueString(item)
+			if err != nil {
+				return "", err
+			}
+			items[i] = s
+		}
+		return strings.Join(items, ","), nil
+	default:
+		return "", fmt.Errorf("unsupported value: %v", value)
+	}
+}
@@ -0,0 +1,165 @@
+//go:build hosttest
+
+package cfg
+
+import (
+	"errors"
+	"io/fs"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"golem-go-project/lib/host"
+)
+
+func setConfigFile(t *testing.T, filePath, content string) {
+	t.Helper()
+	host.SetFakeFile(filePath, []byte(content))
+	t.Setenv(ConfigFileEnvVar, filePath)
+	ResetFile()
+	t.Cleanup(func() {
+		host.ResetFakeFiles()
+		ResetFile()
+	})
+}
+
+func TestLoadConfigFileTOML(t *testing.T) {
+	componentThreeID := uuid.New()
+	setConfigFile(t, "/config/worker.toml", `
+# component ids
+component-three-id = "urn:component:`+componentThreeID.String()+`"
+component_three.routing = 'singleton:aggregator'
+
+[test]
+name = "from file" # trailing comment
+verbose = true
+ratio = 0.5
+offset = -1_000
+tags = [
+  "a",
+  "b", # multiline
+]
+
+[test.rpc]
+timeout = "1m"
+`)
+	t.Setenv("TEST_COMPONENT_TWO_ID", uuid.NewString())
+	t.Setenv("TEST_NAME", "from env")
+	t.Setenv(ComponentThreeIDEnvVar, "")
+	t.Setenv(ComponentThreeRoutingEnvVar, "")
+
+	err := LoadConfigFile()
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+
+	var config testConfig
+	err = Load(&config)
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	if config.Name != "from env" {
+		t.Fatalf("Expected env var to override file value, actual: %s", config.Name)
+	}
+	if !config.Verbose || config.Ratio != 0.5 || config.Offset != -1000 || !reflect.DeepEqual(config.Tags, []string{"a", "b"}) {
+		t.Fatalf("Expected file values, actual: %+v", config)
+	}
+	if config.RPC.Timeout != time.Minute {
+		t.Fatalf("Expected nested table value, actual: %s", config.RPC.Timeout)
+	}
+
+	uri, err := ComponentThreeRoutedWorkerURI("worker-1")
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	workerID, _, err := ParseWorkerURI(uri.Value)
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	if uuid.UUID(workerID.ComponentID) != componentThreeID || workerID.WorkerName != "aggregator" {
+		t.Fatalf("Expected component id and routing from file, actual: %+v", workerID)
+	}
+}
+
+func TestLoadConfigFileJSON(t *testing.T) {
+	setConfigFile(t, "/config/worker.json", `{"test": {"offset": 9007199254740993, "shards": [1, 2], "rpc": {"retries": 5}}}`)
+	t.Setenv("TEST_COMPONENT_TWO_ID", uuid.NewString())
+	t.Setenv("TEST_SHARDS", "")
+
+	err := LoadConfigFile()
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+
+	value, ok := Lookup("TEST_SHARDS")
+	if !ok || value != "1,2" {
+		t.Fatalf("Expected joined array value, actual: %q", value)
+	}
+
+	var config testConfig
+	err = Load(&config)
+	if !errors.As(err, new(FieldErrors)) || !strings.Contains(err.Error(), "TEST_SHARDS") {
+		t.Fatalf("Expected shards error, as the file array is joined with , but the field separator is ;, actual: %+v", err)
+	}
+	if config.Offset != 9007199254740993 || config.RPC.Retries != 5 {
+		t.Fatalf("Expected file values, actual: %+v", config)
+	}
+}
+
+func TestLoadConfigFileWithoutEnvVar(t *testing.T) {
+	t.Setenv(ConfigFileEnvVar, "")
+	ResetFile()
+
+	err := LoadConfigFile()
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	if _, ok := Lookup("TEST_NAME"); ok {
+		t.Fatalf("Expected no file values")
+	}
+}
+
+func TestLoadConfigFileErrors(t *testing.T) {
+	testCases := []struct {
+		name     string
+		filePath string
+		content  string
+		expected string
+	}{
+		{"unknown extension", "/config/worker.yaml", "a: 1", `expected .json or .toml config file, got ".yaml"`},
+		{"invalid json", "/config/worker.json", "{", "json parse failed"},
+		{"invalid toml", "/config/worker.toml", "a = \nb = 1", "toml: line 1: expected value"},
+		{"duplicate key", "/config/worker.toml", "a.b = 1\n[a]\nb = 2", "toml: line 3: duplicate key: b"},
+		{"colliding keys", "/config/worker.json", `{"a-b": 1, "a": {"b": 2}}`, "key A_B: duplicate key"},
+		{"nested array", "/config/worker.json", `{"a": [[1]]}`, "key A: nested arrays are not supported"},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			setConfigFile(t, testCase.filePath, testCase.content)
+
+			err := LoadConfigFile()
+			cfgErr, ok := err.(*Error)
+			if !ok || !strings.Contains(cfgErr.Error(), testCase.expected) {
+				t.Fatalf("Expected config error containing %q, actual: %+v", testCase.expected, err)
+			}
+			if err2 := LoadConfigFile(); err2 != err {
+				t.Fatalf("Expected the first result on later calls, actual: %+v", err2)
+			}
+		})
+	}
+
+	t.Run("missing file", func(t *testing.T) {
+		t.Setenv(ConfigFileEnvVar, "/config/missing.toml")
+		ResetFile()
+		t.Cleanup(ResetFile)
+
+		err := LoadConfigFile()
+		if !errors.Is(err, fs.ErrNotExist) {
+			t.Fatalf("Expected fs.ErrNotExist, actual: %+v", err)
+		}
+	})
+}
@@ -0,0 +1,260 @@
+package cfg
+
+import (
+	"encoding"
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var (
+	componentIDType     = reflect.TypeOf(ComponentID{})
+	uuidType            = reflect.TypeOf(uuid.UUID{})
+	durationType        = reflect.TypeOf(time.Duration(0))
+	textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+)
+
+// FieldError is the error of a config field
+type FieldError struct {
+	// Field is the path of the struct field (e.g. RPC.Timeout), or the name of the dependency for component ids and
+	// routings
+	Field string
+	// EnvVar is the env var of the field
+	EnvVar string
+	Err    error
+}
+
+func (e *FieldError) Error() string {
+	return fmt.Sprintf("%s (%s): %s", e.EnvVar, e.Field, e.Err)
+}
+
+func (e *FieldError) Unwrap() error {
+	return e.Err
+}
+
+// FieldErrors are the errors of all the missing or invalid config fields
+type FieldErrors []*FieldError
+
+func (e FieldErrors) Error() string {
+	messages := make([]string, len(e))
+	for i, err := range e {
+		messages[i] = err.Error()
+	}
+	return strings.Join(messages, "; ")
+}
+
+func (e FieldErrors) Unwrap() []error {
+	errs := make([]error, len(e))
+	for i, err := range e {
+		errs[i] = err
+	}
+	return errs
+}
+
+// ErrMissing is the error of required fields without value
+var ErrMissing = errors.New("missing required value")
+
+// Load fills the fields of the struct pointed to by target from env vars or the loaded config file (see Lookup), based
+// on the field tags:
+//   - `env:"NAME"` sets the field from the NAME env var, if it is set and not empty
+//   - `env:"NAME,required"` also fails if the env var is missing or empty
+//   - `default:"value"` is used if the env var is missing or empty, it cannot be combined with required
+//   - `sep:";"` sets the separator of list values, which is , by default
+//
+// The supported field types are string, bool, the integer and float types, time.Duration, uuid.UUID, ComponentID
+// (see ParseComponentID for the formats), the types implementing encoding.TextUnmarshaler, and slices of these for
+// lists. Fields of struct types without env tag are loaded recursively.
+//
+// All the missing and invalid fields are returned together, as *Error wrapping FieldErrors.
+func Load(target any) error {
+	rv := reflect.ValueOf(target)
+	if rv.Kind() != reflect.Pointer || rv.IsNil() || rv.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("cfg: load: target must be a non-nil pointer to a struct, got %T", target)
+	}
+
+	var fieldErrs FieldErrors
+	err := loadStruct(rv.Elem(), "", &fieldErrs)
+	if err != nil {
+		return fmt.Errorf("cfg: load %T: %w", target, err)
+	}
+	return invalidConfigError(fieldErrs)
+}
+
+// invalidConfigError returns the field errors as *Error, or nil if there are none
+func invalidConfigError(fieldErrs FieldErrors) error {
+	if len(fieldErrs) == 0 {
+		return nil
+	}
+	return &Error{Message: fmt.Sprintf("invalid configuration, %d missing or invalid value(s)", len(fieldErrs)), Err: fieldErrs}
+}
+
+// loadStruct collects the field errors, and returns an error for invalid tags or unsupported types
+func loadStruct(rv reflect.Value, path string, fieldErrs *FieldErrors) error {
+	t := rv.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		fieldPath := field.Name
+		if path != "" {
+			fieldPath = path + "." + field.Name
+		}
+
+		tag, ok := field.Tag.Lookup("env")
+		if !ok {
+			if field.Type.Kind() == reflect.Struct && !isValueType(field.Type) {
+				err := loadStruct(rv.Field(i), fieldPath, fieldErrs)
+				if err != nil {
+					return err
+				}
+			}
+			continue
+		}
+
+		envVar, options, _ := strings.Cut(tag, ",")
+		if envVar == "" || options != "" && options != "required" {
+			return fmt.Errorf("invalid env tag of %s: %q", fieldPath, tag)
+		}
+		defaultValue, hasDefault := field.Tag.Lookup("default")
+		if options == "required" && hasDefault {
+			return fmt.Errorf("field %s: required fields cannot have a default", fieldPath)
+		}
+		err := checkType(field.Type)
+		if err != nil {
+			return fmt.Errorf("field %s: %w", fieldPath, err)
+		}
+		sep := field.Tag.Get("sep")
+		if sep == "" {
+			sep = ","
+		}
+
+		value, _ := Lookup(envVar)
+		if value == "" {
+			value = defaultValue
+		}
+		if value == "" {
+			if options == "required" {
+				*fieldErrs = append(*fieldErrs, &FieldError{Field: fieldPath, EnvVar: envVar, Err: ErrMissing})
+			}
+			continue
+		}
+
+		err = setValue(rv.Field(i), value, sep)
+		if err != nil {
+			*fieldErrs = append(*fieldErrs, &FieldError{Field: fieldPath, EnvVar: envVar, Err: err})
+		}
+	}
+	return nil
+}
+
+var errUnsupportedType = errors.New("unsupported type")
+
+// checkType returns errUnsupportedType if the field type cannot be loaded, so it is reported even without a value
+func checkType(t reflect.Type) error {
+	if t.Kind() == reflect.Slice && !isScalarType(t) {
+		t = t.Elem()
+	}
+	if !isScalarType(t) {
+		return fmt.Errorf("%w: %s", errUnsupportedType, t)
+	}
+	return nil
+}
+
+// isScalarType returns whether the type is loaded from a single value by setValue
+func isScalarType(t reflect.Type) bool {
+	switch {
+	case t == componentIDType, t == uuidType, t == durationType, reflect.PointerTo(t).Implements(textUnmarshalerType):
+		return true
+	}
+	switch t.Kind() {
+	case reflect.String, reflect.Bool,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return true
+	default:
+		return false
+	}
+}
+
+// isValueType returns whether the struct type is loaded from a single value
+func isValueType(t reflect.Type) bool {
+	return reflect.PointerTo(t).Implements(textUnmarshalerType)
+}
+
+func setValue(rv reflect.Value, value string, sep string) error {
+	t := rv.Type()
+	switch {
+	case t == componentIDType:
+		componentID, err := ParseComponentID(value)
+		if err != nil {
+			return err
+		}
+		rv.Set(reflect.ValueOf(componentID))
+		return nil
+	case t == uuidType:
+		id, err := uuid.Parse(strings.TrimSpace(value))
+		if err != nil {
+			return fmt.Errorf("invalid UUID %q, %w", value, err)
+		}
+		rv.Set(reflect.ValueOf(id))
+		return nil
+	case t == durationType:
+		d, err := time.ParseDuration(strings.TrimSpace(value))
+		if err != nil {
+			return fmt.Errorf("invalid duration %q (e.g. 1m30s)", value)
+		}
+		rv.SetInt(int64(d))
+		return nil
+	case reflect.PointerTo(t).Implements(textUnmarshalerType):
+		return rv.Addr().Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(value))
+	}
+
+	switch t.Kind() {
+	case reflect.String:
+		rv.SetString(value)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(strings.TrimSpace(value))
+		if err != nil {
+			return fmt.Errorf("invalid bool %q (e.g. true or false)", value)
+		}
+		rv.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i, err := strconv.ParseInt(strings.TrimSpace(value), 10, t.Bits())
+		if err != nil {
+			return fmt.Errorf("invalid %s %q", t, value)
+		}
+		rv.SetInt(i)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		u, err := strconv.ParseUint(strings.TrimSpace(value), 10, t.Bits())
+		if err != nil {
+			return fmt.Errorf("invalid %s %q", t, value)
+		}
+		rv.SetUint(u)
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(strings.TrimSpace(value), t.Bits())
+		if err != nil {
+			return fmt.Errorf("invalid %s %q", t, value)
+		}
+		rv.SetFloat(f)
+	case reflect.Slice:
+		items := strings.Split(value, sep)
+		slice := reflect.MakeSlice(t, len(items), len(items))
+		for i, item := range items {
+			err := setValue(slice.Index(i), strings.TrimSpace(item), sep)
+			if err != nil {
+				return fmt.Errorf("item #%d: %w", i, err)
+			}
+		}
+		rv.Set(slice)
+	default:
+		return fmt.Errorf("%w: %s", errUnsupportedType, t)
+	}
+	return nil
+}
@@ -0,0 +1,151 @@
+//go:build hosttest
+
+package cfg
+
+import (
+	"errors"
+	"net"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type testRPCConfig struct {
+	Timeout time.Duration `env:"TEST_RPC_TIMEOUT" default:"30s"`
+	Retries uint8         `env:"TEST_RPC_RETRIES" default:"3"`
+}
+
+type testConfig struct {
+	ComponentTwoID ComponentID   `env:"TEST_COMPONENT_TWO_ID,required"`
+	RequestID      uuid.UUID     `env:"TEST_REQUEST_ID"`
+	Name           string        `env:"TEST_NAME" default:"counter"`
+	Verbose        bool          `env:"TEST_VERBOSE"`
+	Offset         int64         `env:"TEST_OFFSET"`
+	Ratio          float64       `env:"TEST_RATIO"`
+	Tags           []string      `env:"TEST_TAGS"`
+	Shards         []int         `env:"TEST_SHARDS" sep:";"`
+	Peers          []ComponentID `env:"TEST_PEERS"`
+	Address        net.IP        `env:"TEST_ADDRESS"`
+	RPC            testRPCConfig
+	Unset          string
+	ignored        string
+}
+
+func TestLoad(t *testing.T) {
+	componentTwoID := uuid.New()
+	peerID := uuid.New()
+	requestID := uuid.New()
+	t.Setenv("TEST_COMPONENT_TWO_ID", "urn:component:"+componentTwoID.String())
+	t.Setenv("TEST_REQUEST_ID", requestID.String())
+	t.Setenv("TEST_VERBOSE", "true")
+	t.Setenv("TEST_OFFSET", "-5")
+	t.Setenv("TEST_RATIO", "0.5")
+	t.Setenv("TEST_TAGS", "a, b,c")
+	t.Setenv("TEST_SHARDS", "1;2")
+	t.Setenv("TEST_PEERS", peerID.String())
+	t.Setenv("TEST_ADDRESS", "10.0.0.1")
+	t.Setenv("TEST_RPC_TIMEOUT", "1m")
+	t.Setenv("Unset", "x")
+
+	var config testConfig
+	err := Load(&config)
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+
+	expected := testConfig{
+		ComponentTwoID: ComponentID(componentTwoID),
+		RequestID:      requestID,
+		Name:           "counter",
+		Verbose:        true,
+		Offset:         -5,
+		Ratio:          0.5,
+		Tags:           []string{"a", "b", "c"},
+		Shards:         []int{1, 2},
+		Peers:          []ComponentID{ComponentID(peerID)},
+		Address:        net.ParseIP("10.0.0.1"),
+		RPC:            testRPCConfig{Timeout: time.Minute, Retries: 3},
+	}
+	if !reflect.DeepEqual(config, expected) {
+		t.Fatalf("Expected: %+v, actual: %+v", expected, config)
+	}
+}
+
+func TestLoadReturnsAllFieldErrors(t *testing.T) {
+	t.Setenv("TEST_COMPONENT_TWO_ID", "")
+	t.Setenv("TEST_VERBOSE", "yes")
+	t.Setenv("TEST_SHARDS", "1;x")
+	t.Setenv("TEST_RPC_TIMEOUT", "30")
+	t.Setenv("TEST_RPC_RETRIES", "300")
+
+	var config testConfig
+	err := Load(&config)
+
+	var cfgErr *Error
+	if !errors.As(err, &cfgErr) {
+		t.Fatalf("Expected *Error, actual: %+v", err)
+	}
+	var fieldErrs FieldErrors
+	if !errors.As(err, &fieldErrs) {
+		t.Fatalf("Expected FieldErrors, actual: %+v", err)
+	}
+	if !errors.Is(err, ErrMissing) {
+		t.Fatalf("Expected ErrMissing, actual: %+v", err)
+	}
+
+	expected := []string{
+		"TEST_COMPONENT_TWO_ID (ComponentTwoID): missing required value",
+		`TEST_VERBOSE (Verbose): invalid bool "yes" (e.g. true or false)`,
+		`TEST_SHARDS (Shards): item #1: invalid int "x"`,
+		`TEST_RPC_TIMEOUT (RPC.Timeout): invalid duration "30" (e.g. 1m30s)`,
+		`TEST_RPC_RETRIES (RPC.Retries): invalid uint8 "300"`,
+	}
+	if len(fieldErrs) != len(expected) {
+		t.Fatalf("Expected %d field errors, actual: %+v", len(expected), err)
+	}
+	for i, fieldErr := range fieldErrs {
+		if fieldErr.Error() != expected[i] {
+			t.Fatalf("Expected field error: %s, actual: %s", expected[i], fieldErr.Error())
+		}
+	}
+	if !strings.HasPrefix(err.Error(), "invalid configuration, 5 missing or invalid value(s), TEST_COMPONENT_TWO_ID") {
+		t.Fatalf("Unexpected error message: %s", err.Error())
+	}
+}
+
+func TestLoadErrors(t *testing.T) {
+	var config testConfig
+	expectLoadErrorContains(t, Load(config), "target must be a non-nil pointer to a struct")
+
+	var invalidTag struct {
+		Value string `env:"TEST_VALUE,optional"`
+	}
+	expectLoadErrorContains(t, Load(&invalidTag), `invalid env tag of Value: "TEST_VALUE,optional"`)
+
+	var requiredWithDefault struct {
+		Value string `env:"TEST_VALUE,required" default:"a"`
+	}
+	expectLoadErrorContains(t, Load(&requiredWithDefault), "field Value: required fields cannot have a default")
+
+	// unsupported types are reported without a value too
+	t.Setenv("TEST_VALUE", "")
+	var unsupported struct {
+		Value map[string]string `env:"TEST_VALUE"`
+	}
+	expectLoadErrorContains(t, Load(&unsupported), "field Value: unsupported type: map[string]string")
+
+	var unsupportedList struct {
+		Values [][]string `env:"TEST_VALUE"`
+	}
+	expectLoadErrorContains(t, Load(&unsupportedList), "field Values: unsupported type: []string")
+}
+
+func expectLoadErrorContains(t *testing.T, err error, expected string) {
+	t.Helper()
+	if err == nil || !strings.Contains(err.Error(), expected) {
+		t.Fatalf("Expected error containing %q, actual: %v", expected, err)
+	}
+}
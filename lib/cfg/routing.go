@@ -0,0 +1,143 @@
+package cfg
+
+import (
+	"fmt"
+	"hash/fnv"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Routing selects the name of the target worker of a dependency for a routing key, which is usually the name of the
+// calling worker. Any name mapping function can be used as Routing.
+type Routing func(key string) string
+
+// SameName routes to the worker with the same name as the key, this is the default routing
+func SameName() Routing {
+	return func(key string) string {
+		return key
+	}
+}
+
+// Singleton routes to the worker with the name, regardless of the key, e.g. for shared aggregators
+func Singleton(workerName string) Routing {
+	return func(string) string {
+		return workerName
+	}
+}
+
+// Sharded routes to one of the workers named <prefix>-0 .. <prefix>-<shards-1>, selected by consistent hashing of the
+// key, so changing the number of shards only moves the keys of the added or removed shards. It panics if shards is
+// less than 1, as there would be no worker to route to.
+func Sharded(shards int, prefix string) Routing {
+	if shards < 1 {
+		panic(fmt.Sprintf("cfg: sharded routing: expected positive number of shards, got %d", shards))
+	}
+	return func(key string) string {
+		return fmt.Sprintf("%s-%d", prefix, jumpHash(hashKey(key), shards))
+	}
+}
+
+var (
+	routings   = map[string]Routing{}
+	routingsMu sync.Mutex
+)
+
+// SetRouting sets the routing of the dependency, which is used unless the routing env var of the dependency is set,
+// e.g. COMPONENT_THREE_ROUTING for component-three
+func SetRouting(componentName string, routing Routing) {
+	routingsMu.Lock()
+	defer routingsMu.Unlock()
+	routings[componentName] = routing
+}
+
+// ResetRoutings removes the routings set with SetRouting
+func ResetRoutings() {
+	routingsMu.Lock()
+	defer routingsMu.Unlock()
+	routings = map[string]Routing{}
+}
+
+// ComponentRouting returns the routing of the dependency from its routing env var (or the config file, see Lookup), or
+// the one set with SetRouting, or SameName. The env var can be set to:
+//   - same-name
+//   - singleton:<worker-name>
+//   - sharded:<shards>, for workers named <component-name>-<shard>
+//   - sharded:<shards>:<prefix>, for workers named <prefix>-<shard>
+func ComponentRouting(componentName, envVar string) (Routing, error) {
+	if value, _ := Lookup(envVar); value != "" {
+		routing, err := ParseRouting(value, componentName)
+		if err != nil {
+			return nil, &Error{Message: fmt.Sprintf("routing parse failed for %s=%s", envVar, value), Err: err}
+		}
+		return routing, nil
+	}
+
+	routingsMu.Lock()
+	defer routingsMu.Unlock()
+	if routing, ok := routings[componentName]; ok {
+		return routing, nil
+	}
+	return SameName(), nil
+}
+
+// ParseRouting parses a routing, see ComponentRouting for the format, the default prefix of sharded routings is
+// defaultPrefix
+func ParseRouting(value, defaultPrefix string) (Routing, error) {
+	kind, args, _ := strings.Cut(strings.TrimSpace(value), ":")
+	switch kind {
+	case "same-name":
+		if args != "" {
+			return nil, fmt.Errorf("unexpected arguments for same-name routing: %q", args)
+		}
+		return SameName(), nil
+	case "singleton":
+		err := ValidateWorkerName(args)
+		if err != nil {
+			return nil, fmt.Errorf("singleton routing: %w", err)
+		}
+		return Singleton(args), nil
+	case "sharded":
+		shardsArg, prefix, hasPrefix := strings.Cut(args, ":")
+		shards, err := strconv.Atoi(shardsArg)
+		if err != nil || shards < 1 {
+			return nil, fmt.Errorf("sharded routing: expected positive number of shards, got %q", shardsArg)
+		}
+		if !hasPrefix {
+			prefix = defaultPrefix
+		}
+		err = ValidateWorkerName(prefix)
+		if err != nil {
+			return nil, fmt.Errorf("sharded routing: prefix: %w", err)
+		}
+		return Sharded(shards, prefix), nil
+	default:
+		return nil, fmt.Errorf("expected same-name, singleton:<worker-name> or sharded:<shards>[:<prefix>], got %q", value)
+	}
+}
+
+// routedWorkerURIF returns the URI of the worker of the component, selected by the routing of the component for the key
+func routedWorkerURIF(componentName, routingEnvVar string, getComponentID func() (ComponentID, error), key string) (URI, error) {
+	routing, err := ComponentRouting(componentName, routingEnvVar)
+	if err != nil {
+		return URI{}, err
+	}
+	return workerURIF(getComponentID, routing(key))
+}
+
+func hashKey(key string) uint64 {
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(key))
+	return h.Sum64()
+}
+
+// jumpHash is the jump consistent hash of Lamping and Veach, it maps the key to a bucket in [0, buckets)
+func jumpHash(key uint64, buckets int) int {
+	var b, j int64 = -1, 0
+	for j < int64(buckets) {
+		b = j
+		key = key*2862933555777941757 + 1
+		j = int64(float64(b+1) * (float64(int64(1)<<31) / float64((key>>33)+1)))
+	}
+	return int(b)
+}
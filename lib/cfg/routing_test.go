@@ -0,0 +1,141 @@
+//go:build hosttest
+
+package cfg
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestParseRouting(t *testing.T) {
+	tests := []struct {
+		value    string
+		key      string
+		expected string
+	}{
+		{value: "same-name", key: "worker-1", expected: "worker-1"},
+		{value: "singleton:aggregator", key: "worker-1", expected: "aggregator"},
+		{value: "sharded:1", key: "worker-1", expected: "component-three-0"},
+		{value: "sharded:1:shard", key: "worker-1", expected: "shard-0"},
+	}
+	for _, test := range tests {
+		routing, err := ParseRouting(test.value, "component-three")
+		if err != nil {
+			t.Fatalf("%+v", err)
+		}
+		if actual := routing(test.key); actual != test.expected {
+			t.Fatalf("Expected worker name for %s, %s: %s, actual: %s", test.value, test.key, test.expected, actual)
+		}
+	}
+}
+
+func TestParseRoutingErrors(t *testing.T) {
+	tests := map[string]string{
+		"":                 "expected same-name, singleton:<worker-name> or sharded:<shards>[:<prefix>]",
+		"random":           "expected same-name, singleton:<worker-name> or sharded:<shards>[:<prefix>]",
+		"same-name:x":      "unexpected arguments for same-name routing",
+		"singleton":        "singleton routing: invalid worker name: empty",
+		"singleton:a b":    "singleton routing: invalid worker name: illegal character",
+		"sharded:0":        "sharded routing: expected positive number of shards",
+		"sharded:x":        "sharded routing: expected positive number of shards",
+		"sharded:2:":       "sharded routing: prefix: invalid worker name: empty",
+		"sharded:2:a\tb:c": "sharded routing: prefix: invalid worker name: illegal character",
+	}
+	for value, expected := range tests {
+		_, err := ParseRouting(value, "component-three")
+		if err == nil || !strings.Contains(err.Error(), expected) {
+			t.Fatalf("Expected error for %q containing %q, actual: %v", value, expected, err)
+		}
+	}
+}
+
+func TestShardedRoutingIsConsistent(t *testing.T) {
+	routing := Sharded(4, "shard")
+	grownRouting := Sharded(5, "shard")
+
+	counts := map[string]int{}
+	moved := 0
+	for i := 0; i < 1000; i++ {
+		key := fmt.Sprintf("worker-%d", i)
+		workerName := routing(key)
+		if workerName != routing(key) {
+			t.Fatalf("Expected the same worker name for %s", key)
+		}
+		counts[workerName]++
+
+		// adding a shard only moves keys to the new shard
+		if grownWorkerName := grownRouting(key); grownWorkerName != workerName {
+			if grownWorkerName != "shard-4" {
+				t.Fatalf("Expected %s to stay on %s or move to shard-4, actual: %s", key, workerName, grownWorkerName)
+			}
+			moved++
+		}
+	}
+
+	if len(counts) != 4 {
+		t.Fatalf("Expected keys on 4 shards, actual: %v", counts)
+	}
+	for workerName, count := range counts {
+		if count < 150 {
+			t.Fatalf("Expected balanced shards, %s has %d keys: %v", workerName, count, counts)
+		}
+	}
+	if moved < 100 || moved > 300 {
+		t.Fatalf("Expected about 1/5 of the keys to move, actual: %d", moved)
+	}
+}
+
+func TestShardedPanicsWithoutShards(t *testing.T) {
+	for _, shards := range []int{0, -1} {
+		func() {
+			defer func() {
+				r := recover()
+				if r == nil || !strings.Contains(fmt.Sprint(r), "expected positive number of shards") {
+					t.Fatalf("Expected panic for %d shards, actual: %v", shards, r)
+				}
+			}()
+			Sharded(shards, "shard")
+		}()
+	}
+}
+
+func TestComponentRouting(t *testing.T) {
+	t.Setenv(ComponentThreeIDEnvVar, uuid.UUID(testComponentID).String())
+	t.Setenv(ComponentThreeRoutingEnvVar, "")
+	t.Cleanup(ResetRoutings)
+
+	expectRoutedWorkerName := func(expected string) {
+		t.Helper()
+		uri, err := ComponentThreeRoutedWorkerURI("worker-1")
+		if err != nil {
+			t.Fatalf("%+v", err)
+		}
+		workerID, _, err := ParseWorkerURI(uri.Value)
+		if err != nil {
+			t.Fatalf("%+v", err)
+		}
+		if workerID.WorkerName != expected {
+			t.Fatalf("Expected routed worker name: %s, actual: %s", expected, workerID.WorkerName)
+		}
+	}
+
+	// same-name by default
+	expectRoutedWorkerName("worker-1")
+
+	// the routing set in code is used without env var
+	SetRouting("component-three", func(key string) string { return "mapped-" + key })
+	expectRoutedWorkerName("mapped-worker-1")
+
+	// the env var overrides the routing set in code
+	t.Setenv(ComponentThreeRoutingEnvVar, "singleton:aggregator")
+	expectRoutedWorkerName("aggregator")
+
+	t.Setenv(ComponentThreeRoutingEnvVar, "sharded:two")
+	_, err := ComponentThreeRoutedWorkerURI("worker-1")
+	if cfgErr, ok := err.(*Error); !ok || !strings.HasPrefix(cfgErr.Message, "routing parse failed for COMPONENT_THREE_ROUTING=sharded:two") {
+		t.Fatalf("Expected routing config error, actual: %+v", err)
+	}
+}
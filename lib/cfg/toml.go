@@ -0,0 +1,338 @@
+package cfg
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// parseTOML parses the subset of TOML used by config files into nested maps: tables ([a.b]), key/value pairs with
+// bare, quoted and dotted keys, basic and literal strings, integers, floats, booleans and (multiline) arrays of these.
+// Inline tables, arrays of tables, multiline strings and date-times are not supported.
+func parseTOML(data string) (map[string]any, error) {
+	p := &tomlParser{data: data, line: 1}
+	root := map[string]any{}
+	table := root
+
+	for {
+		p.skipSpaceAndComments(true)
+		if p.eof() {
+			return root, nil
+		}
+
+		if p.peek() == '[' {
+			p.pos++
+			if p.peek() == '[' {
+				return nil, p.errorf("arrays of tables are not supported")
+			}
+			keys, err := p.parseKey()
+			if err != nil {
+				return nil, err
+			}
+			p.skipSpace()
+			if p.peek() != ']' {
+				return nil, p.errorf("expected ] after table name")
+			}
+			p.pos++
+			table, err = tomlTable(root, keys)
+			if err != nil {
+				return nil, p.errorf("%s", err)
+			}
+		} else {
+			keys, err := p.parseKey()
+			if err != nil {
+				return nil, err
+			}
+			p.skipSpace()
+			if p.peek() != '=' {
+				return nil, p.errorf("expected = after key")
+			}
+			p.pos++
+			p.skipSpace()
+			value, err := p.parseValue()
+			if err != nil {
+				return nil, err
+			}
+			parent, err := tomlTable(table, keys[:len(keys)-1])
+			if err != nil {
+				return nil, p.errorf("%s", err)
+			}
+			key := keys[len(keys)-1]
+			if _, ok := parent[key]; ok {
+				return nil, p.errorf("duplicate key: %s", strings.Join(keys, "."))
+			}
+			parent[key] = value
+		}
+
+		p.skipSpaceAndComments(false)
+		if !p.eof() && p.peek() != '\n' {
+			return nil, p.errorf("expected end of line")
+		}
+	}
+}
+
+// tomlTable returns the nested table of the keys, creating the missing ones
+func tomlTable(root map[string]any, keys []string) (map[string]any, error) {
+	table := root
+	for i, key := range keys {
+		value, ok := table[key]
+		if !ok {
+			child := map[string]any{}
+			table[key] = child
+			table = child
+			continue
+		}
+		child, ok := value.(map[string]any)
+		if !ok {
+			return nil, fmt.Errorf("key is not a table: %s", strings.Join(keys[:i+1], "."))
+		}
+		table = child
+	}
+	return table, nil
+}
+
+type tomlParser struct {
+	data string
+	pos  int
+	line int
+}
+
+func (p *tomlParser) eof() bool {
+	return p.pos >= len(p.data)
+}
+
+func (p *tomlParser) peek() byte {
+	if p.eof() {
+		return 0
+	}
+	return p.data[p.pos]
+}
+
+func (p *tomlParser) errorf(format string, args ...any) error {
+	return fmt.Errorf("toml: line %d: %s", p.line, fmt.Sprintf(format, args...))
+}
+
+func (p *tomlParser) skipSpace() {
+	for !p.eof() && (p.peek() == ' ' || p.peek() == '\t' || p.peek() == '\r') {
+		p.pos++
+	}
+}
+
+// skipSpaceAndComments skips spaces and comments, and also new lines if newLines is set
+func (p *tomlParser) skipSpaceAndComments(newLines bool) {
+	for !p.eof() {
+		switch c := p.peek(); {
+		case c == ' ' || c == '\t' || c == '\r':
+			p.pos++
+		case c == '\n' && newLines:
+			p.pos++
+			p.line++
+		case c == '#':
+			for !p.eof() && p.peek() != '\n' {
+				p.pos++
+			}
+		default:
+			return
+		}
+	}
+}
+
+// parseKey parses a bare, quoted or dotted key into its parts
+func (p *tomlParser) parseKey() ([]string, error) {
+	var keys []string
+	for {
+		p.skipSpace()
+		var key string
+		switch c := p.peek(); {
+		case c == '"':
+			s, err := p.parseBasicString()
+			if err != nil {
+				return nil, err
+			}
+			key = s
+		case c == '\'':
+			s, err := p.parseLiteralString()
+			if err != nil {
+				return nil, err
+			}
+			key = s
+		default:
+			start := p.pos
+			for !p.eof() && isTOMLBareKeyChar(p.peek()) {
+				p.pos++
+			}
+			if start == p.pos {
+				return nil, p.errorf("expected key")
+			}
+			key = p.data[start:p.pos]
+		}
+		keys = append(keys, key)
+
+		p.skipSpace()
+		if p.peek() != '.' {
+			return keys, nil
+		}
+		p.pos++
+	}
+}
+
+func isTOMLBareKeyChar(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '_' || c == '-'
+}
+
+func (p *tomlParser) parseValue() (any, error) {
+	switch c := p.peek(); {
+	case c == '"':
+		if strings.HasPrefix(p.data[p.pos:], `"""`) {
+			return nil, p.errorf("multiline strings are not supported")
+		}
+		return p.parseBasicString()
+	case c == '\'':
+		if strings.HasPrefix(p.data[p.pos:], `'''`) {
+			return nil, p.errorf("multiline strings are not supported")
+		}
+		return p.parseLiteralString()
+	case c == '[':
+		return p.parseArray()
+	case c == '{':
+		return nil, p.errorf("inline tables are not supported")
+	default:
+		start := p.pos
+		for !p.eof() && !strings.ContainsRune(" \t\r\n#,]", rune(p.peek())) {
+			p.pos++
+		}
+		return p.parseScalar(p.data[start:p.pos])
+	}
+}
+
+func (p *tomlParser) parseScalar(s string) (any, error) {
+	switch s {
+	case "true":
+		return true, nil
+	case "false":
+		return false, nil
+	case "inf", "+inf", "-inf", "nan", "+nan", "-nan":
+		f, _ := strconv.ParseFloat(strings.TrimPrefix(s, "+"), 64)
+		return f, nil
+	}
+	if s == "" {
+		return nil, p.errorf("expected value")
+	}
+
+	number := strings.ReplaceAll(s, "_", "")
+	digits := strings.TrimLeft(number, "+-")
+	switch {
+	case strings.HasPrefix(number, "0x") || strings.HasPrefix(number, "0o") || strings.HasPrefix(number, "0b"):
+		i, err := strconv.ParseInt(number, 0, 64)
+		if err == nil {
+			return i, nil
+		}
+	case len(digits) > 1 && digits[0] == '0' && digits[1] >= '0' && digits[1] <= '9':
+		// leading zeros are not allowed
+	case !strings.ContainsAny(digits, ".eE"):
+		i, err := strconv.ParseInt(number, 10, 64)
+		if err == nil {
+			return i, nil
+		}
+	case !strings.ContainsAny(digits, "xXoObBpP"):
+		f, err := strconv.ParseFloat(number, 64)
+		if err == nil {
+			return f, nil
+		}
+	}
+	return nil, p.errorf("invalid value: %s", s)
+}
+
+func (p *tomlParser) parseArray() ([]any, error) {
+	p.pos++
+	items := []any{}
+	for {
+		p.skipSpaceAndComments(true)
+		if p.peek() == ']' {
+			p.pos++
+			return items, nil
+		}
+		item, err := p.parseValue()
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+
+		p.skipSpaceAndComments(true)
+		switch p.peek() {
+		case ',':
+			p.pos++
+		case ']':
+			p.pos++
+			return items, nil
+		default:
+			return nil, p.errorf("expected , or ] in array")
+		}
+	}
+}
+
+func (p *tomlParser) parseLiteralString() (string, error) {
+	p.pos++
+	end := strings.IndexAny(p.data[p.pos:], "'\n")
+	if end < 0 || p.data[p.pos+end] != '\'' {
+		return "", p.errorf("unterminated string")
+	}
+	s := p.data[p.pos : p.pos+end]
+	p.pos += end + 1
+	return s, nil
+}
+
+func (p *tomlParser) parseBasicString() (string, error) {
+	p.pos++
+	var sb strings.Builder
+	for {
+		if p.eof() || p.peek() == '\n' {
+			return "", p.errorf("unterminated string")
+		}
+		c := p.peek()
+		p.pos++
+		switch c {
+		case '"':
+			return sb.String(), nil
+		case '\\':
+			if p.eof() {
+				return "", p.errorf("unterminated string")
+			}
+			escape := p.peek()
+			p.pos++
+			switch escape {
+			case 'b':
+				sb.WriteByte('\b')
+			case 't':
+				sb.WriteByte('\t')
+			case 'n':
+				sb.WriteByte('\n')
+			case 'f':
+				sb.WriteByte('\f')
+			case 'r':
+				sb.WriteByte('\r')
+			case '"', '\\':
+				sb.WriteByte(escape)
+			case 'u', 'U':
+				size := 4
+				if escape == 'U' {
+					size = 8
+				}
+				if p.pos+size > len(p.data) {
+					return "", p.errorf("invalid unicode escape")
+				}
+				code, err := strconv.ParseUint(p.data[p.pos:p.pos+size], 16, 32)
+				if err != nil || !utf8.ValidRune(rune(code)) {
+					return "", p.errorf("invalid unicode escape: %s", p.data[p.pos:p.pos+size])
+				}
+				p.pos += size
+				sb.WriteRune(rune(code))
+			default:
+				return "", p.errorf("invalid escape: \\%c", escape)
+			}
+		default:
+			sb.WriteByte(c)
+		}
+	}
+}
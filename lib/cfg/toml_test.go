@@ -0,0 +1,94 @@
+//go:build hosttest
+
+package cfg
+
+import (
+	"math"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseTOML(t *testing.T) {
+	document, err := parseTOML(`
+title = "basic \"string\" \u00e9\tend"
+path = 'C:\literal'
+"quoted key" = 1
+a.b.c = true
+
+[numbers]
+int = +42
+zero = 0
+hex = 0xff
+oct = 0o17
+bin = 0b101
+big = 1_000_000
+float = -3.5e2
+inf = inf
+
+[ "server" . limits ]
+ports = [ 8080, 8081 ]
+empty = []
+mixed = [
+  "x", # comment
+  'y',
+]
+`)
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+
+	expected := map[string]any{
+		"title":      "basic \"string\" é\tend",
+		"path":       `C:\literal`,
+		"quoted key": int64(1),
+		"a":          map[string]any{"b": map[string]any{"c": true}},
+		"numbers": map[string]any{
+			"int":   int64(42),
+			"zero":  int64(0),
+			"hex":   int64(255),
+			"oct":   int64(15),
+			"bin":   int64(5),
+			"big":   int64(1000000),
+			"float": -350.0,
+			"inf":   math.Inf(1),
+		},
+		"server": map[string]any{
+			"limits": map[string]any{
+				"ports": []any{int64(8080), int64(8081)},
+				"empty": []any{},
+				"mixed": []any{"x", "y"},
+			},
+		},
+	}
+	if !reflect.DeepEqual(document, expected) {
+		t.Fatalf("Expected: %#v, actual: %#v", expected, document)
+	}
+}
+
+func TestParseTOMLErrors(t *testing.T) {
+	testCases := []struct {
+		data     string
+		expected string
+	}{
+		{"a = 1\nb = 2 c", "line 2: expected end of line"},
+		{"a 1", "line 1: expected = after key"},
+		{"a = \"open", "line 1: unterminated string"},
+		{`a = "\x"`, `line 1: invalid escape: \x`},
+		{"a = 012", "line 1: invalid value: 012"},
+		{"a = yes", "line 1: invalid value: yes"},
+		{"a = [1 2]", "line 1: expected , or ] in array"},
+		{"a = {b = 1}", "line 1: inline tables are not supported"},
+		{"[[a]]", "line 1: arrays of tables are not supported"},
+		{`a = """b"""`, "line 1: multiline strings are not supported"},
+		{"a = 1\na.b = 2", "line 2: key is not a table: a"},
+		{"[a\nb = 1", "line 1: expected ] after table name"},
+	}
+
+	for _, testCase := range testCases {
+		_, err := parseTOML(testCase.data)
+		if err == nil || !strings.Contains(err.Error(), testCase.expected) {
+			t.Fatalf("Expected error containing %q for %q, actual: %+v", testCase.expected, testCase.data, err)
+		}
+	}
+}
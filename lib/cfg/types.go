@@ -0,0 +1,14 @@
+//go:build !hosttest
+
+package cfg
+
+import (
+	"github.com/golemcloud/golem-go/binding"
+	"github.com/golemcloud/golem-go/golemhost"
+)
+
+type ComponentID = golemhost.ComponentID
+
+type WorkerID = golemhost.WorkerID
+
+type URI = binding.GolemRpc0_1_0_TypesUri
@@ -0,0 +1,20 @@
+//go:build hosttest
+
+package cfg
+
+import (
+	"github.com/google/uuid"
+)
+
+// NOTE: the golem-go types can only be used in wasm builds, so host tests use structurally identical types instead
+
+type ComponentID uuid.UUID
+
+type WorkerID struct {
+	ComponentID ComponentID
+	WorkerName  string
+}
+
+type URI struct {
+	Value string
+}
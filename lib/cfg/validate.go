@@ -0,0 +1,125 @@
+package cfg
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// Requirement checks configuration values needed by a component, returning the missing and invalid ones as
+// FieldErrors, see Validate
+type Requirement func() error
+
+// Dependency requires the component id env var of the dependency, and its routing env var to be valid if it is set
+func Dependency(componentName string) Requirement {
+	return func() error {
+		idEnvVar, ok := ComponentIDEnvVars[componentName]
+		if !ok {
+			return fmt.Errorf("cfg: unknown dependency: %s", componentName)
+		}
+
+		var fieldErrs FieldErrors
+		value, _ := Lookup(idEnvVar)
+		if value == "" {
+			fieldErrs = append(fieldErrs, &FieldError{Field: componentName, EnvVar: idEnvVar, Err: ErrMissing})
+		} else if _, err := ParseComponentID(value); err != nil {
+			fieldErrs = append(fieldErrs, &FieldError{Field: componentName, EnvVar: idEnvVar, Err: err})
+		}
+
+		routingEnvVar := ComponentRoutingEnvVars[componentName]
+		if value, _ := Lookup(routingEnvVar); value != "" {
+			if _, err := ParseRouting(value, componentName); err != nil {
+				fieldErrs = append(fieldErrs, &FieldError{Field: componentName, EnvVar: routingEnvVar, Err: err})
+			}
+		}
+
+		if len(fieldErrs) == 0 {
+			return nil
+		}
+		return fieldErrs
+	}
+}
+
+// DependenciesOf requires the configuration of all the RPC dependencies of the component, see Dependency and
+// ComponentDependencies
+func DependenciesOf(componentName string) Requirement {
+	return func() error {
+		if _, ok := ComponentIDEnvVars[componentName]; !ok {
+			return fmt.Errorf("cfg: unknown component: %s", componentName)
+		}
+		requirements := make([]Requirement, len(ComponentDependencies[componentName]))
+		for i, dependency := range ComponentDependencies[componentName] {
+			requirements[i] = Dependency(dependency)
+		}
+		return collectFieldErrors(requirements)
+	}
+}
+
+// Struct requires the config struct to be loaded without errors, and fills it, see Load
+func Struct(target any) Requirement {
+	return func() error {
+		return Load(target)
+	}
+}
+
+// Validate checks all the requirements, and returns all the missing and invalid values together, as *Error wrapping
+// FieldErrors, e.g. invalid configuration, 2 missing or invalid value(s), COMPONENT_TWO_ID (component-two): missing
+// required value; COMPONENT_THREE_ROUTING (component-three): ...
+func Validate(requirements ...Requirement) error {
+	return collectFieldErrors(requirements)
+}
+
+func collectFieldErrors(requirements []Requirement) error {
+	var fieldErrs FieldErrors
+	for _, requirement := range requirements {
+		err := requirement()
+		var errs FieldErrors
+		if errors.As(err, &errs) {
+			fieldErrs = append(fieldErrs, errs...)
+		} else if err != nil {
+			return err
+		}
+	}
+	return invalidConfigError(fieldErrs)
+}
+
+// Startup loads the config file and validates the requirements of a component at the first invocation of the worker,
+// so missing or invalid configuration fails the invocation, instead of being found when it is first used
+type Startup struct {
+	requirements []Requirement
+
+	mu   sync.Mutex
+	done bool
+	err  error
+}
+
+// NewStartup returns the Startup of the requirements, it is usually stored in a package variable of the component
+func NewStartup(requirements ...Requirement) *Startup {
+	return &Startup{requirements: requirements}
+}
+
+// Init loads the config file (see LoadConfigFile) and validates the requirements (see Validate) at the first call,
+// later calls return the result of the first one. It should be called at the start of the exported functions, after
+// stdinit.Init.
+func (s *Startup) Init() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.done {
+		return s.err
+	}
+	s.done = true
+
+	s.err = LoadConfigFile()
+	if s.err == nil {
+		s.err = Validate(s.requirements...)
+	}
+	return s.err
+}
+
+// Reset makes the next Init call load and validate again, e.g. between tests
+func (s *Startup) Reset() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.done, s.err = false, nil
+	ResetFile()
+}
@@ -0,0 +1,91 @@
+//go:build hosttest
+
+package cfg
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"golem-go-project/lib/host"
+)
+
+func TestValidateReturnsAllFieldErrors(t *testing.T) {
+	t.Setenv(ComponentTwoIDEnvVar, "")
+	t.Setenv(ComponentTwoRoutingEnvVar, "")
+	t.Setenv(ComponentThreeIDEnvVar, "urn:worker:x")
+	t.Setenv(ComponentThreeRoutingEnvVar, "round-robin")
+	t.Setenv("TEST_COMPONENT_TWO_ID", "")
+	t.Setenv("TEST_VERBOSE", "")
+
+	var config testConfig
+	err := Validate(DependenciesOf("component-one"), Struct(&config))
+
+	var cfgErr *Error
+	if !errors.As(err, &cfgErr) || cfgErr.Message != "invalid configuration, 4 missing or invalid value(s)" {
+		t.Fatalf("Expected config error, actual: %+v", err)
+	}
+	var fieldErrs FieldErrors
+	if !errors.As(err, &fieldErrs) {
+		t.Fatalf("Expected field errors, actual: %+v", err)
+	}
+	expected := []string{
+		"COMPONENT_TWO_ID (component-two): missing required value",
+		"COMPONENT_THREE_ID (component-three): expected component URN",
+		"COMPONENT_THREE_ROUTING (component-three): expected same-name",
+		"TEST_COMPONENT_TWO_ID (ComponentTwoID): missing required value",
+	}
+	for i, fieldErr := range fieldErrs {
+		if !strings.HasPrefix(fieldErr.Error(), expected[i]) {
+			t.Fatalf("Expected field error #%d: %s, actual: %s", i, expected[i], fieldErr)
+		}
+	}
+	if !errors.Is(err, ErrMissing) {
+		t.Fatalf("Expected ErrMissing in %+v", err)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	t.Setenv(ComponentThreeIDEnvVar, "urn:component:"+uuid.NewString())
+	t.Setenv(ComponentThreeRoutingEnvVar, "singleton:aggregator")
+
+	err := Validate(DependenciesOf("component-two"), DependenciesOf("component-three"))
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+
+	err = Validate(Dependency("component-five"))
+	if err == nil || err.Error() != "cfg: unknown dependency: component-five" {
+		t.Fatalf("Expected unknown dependency error, actual: %+v", err)
+	}
+}
+
+func TestStartupKeepsFirstResult(t *testing.T) {
+	host.SetFakeFile("/config/worker.toml", []byte(`component-three-id = "`+uuid.NewString()+`"`))
+	t.Setenv(ConfigFileEnvVar, "/config/worker.toml")
+	t.Setenv(ComponentThreeIDEnvVar, "")
+	t.Setenv(ComponentThreeRoutingEnvVar, "")
+	startup := NewStartup(DependenciesOf("component-two"))
+	t.Cleanup(host.ResetFakeFiles)
+	t.Cleanup(startup.Reset)
+
+	// the component id is read from the config file
+	err := startup.Init()
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+
+	t.Setenv(ComponentThreeRoutingEnvVar, "sharded:0")
+	err = startup.Init()
+	if err != nil {
+		t.Fatalf("Expected the first result, actual: %+v", err)
+	}
+
+	startup.Reset()
+	err = startup.Init()
+	if err == nil || !strings.Contains(err.Error(), "COMPONENT_THREE_ROUTING (component-three)") {
+		t.Fatalf("Expected routing error after reset, actual: %+v", err)
+	}
+}
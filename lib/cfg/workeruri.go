@@ -0,0 +1,104 @@
+package cfg
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+)
+
+// workerURNPrefix is the prefix of worker URIs, which are followed by <component-id>/<worker-name>[/<function-name>]
+const workerURNPrefix = "urn:worker:"
+
+// uuidURNPrefix is the prefix of the component ids in the worker URIs built by WorkerURI
+const uuidURNPrefix = "urn:uuid:"
+
+// legacyWorkerURIPrefix is the prefix of worker URIs returned by older golem versions
+const legacyWorkerURIPrefix = "worker://"
+
+// MaxWorkerNameLength is the maximum length of worker names in bytes
+const MaxWorkerNameLength = 512
+
+// ValidateWorkerName checks that the worker name is not empty, not longer than MaxWorkerNameLength, valid UTF-8, and
+// has no whitespace, control characters or /, which separates the worker name in worker URIs.
+func ValidateWorkerName(workerName string) error {
+	if workerName == "" {
+		return errors.New("invalid worker name: empty")
+	}
+	if len(workerName) > MaxWorkerNameLength {
+		return fmt.Errorf("invalid worker name: longer than %d bytes: %q...", MaxWorkerNameLength, workerName[:32])
+	}
+	if !utf8.ValidString(workerName) {
+		return fmt.Errorf("invalid worker name: invalid UTF-8: %q", workerName)
+	}
+	for i, r := range workerName {
+		if unicode.IsSpace(r) || unicode.IsControl(r) || r == '/' {
+			return fmt.Errorf("invalid worker name: illegal character %q at %d: %q", r, i, workerName)
+		}
+	}
+	return nil
+}
+
+// WorkerURI returns the URI of the worker, urn:worker:urn:uuid:<component-id>/<worker-name>. No escaping is applied,
+// the worker name is passed to golem as is, so only the names accepted by ValidateWorkerName can be addressed.
+func WorkerURI(workerID WorkerID) URI {
+	return URI{
+		Value: fmt.Sprintf("%s%s/%s", workerURNPrefix, uuid.UUID(workerID.ComponentID).URN(), workerID.WorkerName),
+	}
+}
+
+// WorkerFunctionURI returns the URI of the worker's function, like the ones returned by golem's get-self-uri,
+// urn:worker:urn:uuid:<component-id>/<worker-name>/<function-name>. Like in WorkerURI, the names are not escaped, the
+// function name is the rest of the URI, so it can contain / (e.g. golem:component-one/component-one-api.{add}).
+func WorkerFunctionURI(workerID WorkerID, functionName string) URI {
+	uri := WorkerURI(workerID)
+	uri.Value += "/" + functionName
+	return uri
+}
+
+// ParseWorkerURI parses the worker URI, and returns the worker id and the function name, which is empty for URIs
+// without function name. Besides the URIs of WorkerURI and WorkerFunctionURI, the component id can be a plain UUID,
+// and worker:// URIs are also accepted. The names are not unescaped, like in WorkerURI.
+func ParseWorkerURI(value string) (WorkerID, string, error) {
+	var rest string
+	switch {
+	case strings.HasPrefix(value, workerURNPrefix):
+		rest = strings.TrimPrefix(value[len(workerURNPrefix):], uuidURNPrefix)
+	case strings.HasPrefix(value, legacyWorkerURIPrefix):
+		rest = value[len(legacyWorkerURIPrefix):]
+	default:
+		return WorkerID{}, "", fmt.Errorf("worker URI parse failed for %q, expected %s prefix", value, workerURNPrefix)
+	}
+
+	parts := strings.SplitN(rest, "/", 3)
+	if len(parts) < 2 {
+		return WorkerID{}, "", fmt.Errorf(
+			"worker URI parse failed for %q, expected %s<component-id>/<worker-name>[/<function-name>]",
+			value, workerURNPrefix,
+		)
+	}
+
+	componentID, err := uuid.Parse(parts[0])
+	if err != nil {
+		return WorkerID{}, "", fmt.Errorf("worker URI parse failed for %q, invalid component id, %w", value, err)
+	}
+
+	workerName := parts[1]
+	err = ValidateWorkerName(workerName)
+	if err != nil {
+		return WorkerID{}, "", fmt.Errorf("worker URI parse failed for %q, %w", value, err)
+	}
+
+	var functionName string
+	if len(parts) == 3 {
+		functionName = parts[2]
+		if functionName == "" {
+			return WorkerID{}, "", fmt.Errorf("worker URI parse failed for %q, empty function name", value)
+		}
+	}
+
+	return WorkerID{ComponentID: ComponentID(componentID), WorkerName: workerName}, functionName, nil
+}
@@ -0,0 +1,124 @@
+//go:build hosttest
+
+package cfg
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+var testComponentID = ComponentID(uuid.MustParse("4bcc2fb0-31c5-4fb1-9e6d-4f2ae0e5c8e6"))
+
+func TestWorkerURIRoundTrip(t *testing.T) {
+	tests := []struct {
+		workerName   string
+		functionName string
+		expected     string
+	}{
+		{workerName: "worker-1", expected: "urn:worker:urn:uuid:4bcc2fb0-31c5-4fb1-9e6d-4f2ae0e5c8e6/worker-1"},
+		{workerName: "a?c#d%e", expected: "urn:worker:urn:uuid:4bcc2fb0-31c5-4fb1-9e6d-4f2ae0e5c8e6/a?c#d%e"},
+		{workerName: "név", expected: "urn:worker:urn:uuid:4bcc2fb0-31c5-4fb1-9e6d-4f2ae0e5c8e6/név"},
+		{
+			workerName:   "worker-1",
+			functionName: "golem:component-one/component-one-api.{add}",
+			expected:     "urn:worker:urn:uuid:4bcc2fb0-31c5-4fb1-9e6d-4f2ae0e5c8e6/worker-1/golem:component-one/component-one-api.{add}",
+		},
+	}
+
+	for _, test := range tests {
+		workerID := WorkerID{ComponentID: testComponentID, WorkerName: test.workerName}
+		uri := WorkerURI(workerID)
+		if test.functionName != "" {
+			uri = WorkerFunctionURI(workerID, test.functionName)
+		}
+		if uri.Value != test.expected {
+			t.Fatalf("Expected URI: %s, actual: %s", test.expected, uri.Value)
+		}
+
+		actualWorkerID, actualFunctionName, err := ParseWorkerURI(uri.Value)
+		if err != nil {
+			t.Fatalf("%+v", err)
+		}
+		if actualWorkerID != workerID || actualFunctionName != test.functionName {
+			t.Fatalf("Expected: %+v, %q, actual: %+v, %q", workerID, test.functionName, actualWorkerID, actualFunctionName)
+		}
+	}
+}
+
+func TestParseWorkerURIAcceptsOtherForms(t *testing.T) {
+	expected := WorkerID{ComponentID: testComponentID, WorkerName: "worker-1"}
+	for _, value := range []string{
+		"urn:worker:4bcc2fb0-31c5-4fb1-9e6d-4f2ae0e5c8e6/worker-1",
+		"worker://4bcc2fb0-31c5-4fb1-9e6d-4f2ae0e5c8e6/worker-1",
+	} {
+		actual, functionName, err := ParseWorkerURI(value)
+		if err != nil {
+			t.Fatalf("%+v", err)
+		}
+		if actual != expected || functionName != "" {
+			t.Fatalf("Expected worker id for %s: %+v, actual: %+v, %q", value, expected, actual, functionName)
+		}
+	}
+}
+
+func TestParseWorkerURIKeepsSlashesOfFunctionName(t *testing.T) {
+	workerID, functionName, err := ParseWorkerURI("urn:worker:urn:uuid:4bcc2fb0-31c5-4fb1-9e6d-4f2ae0e5c8e6/worker-1/a/b/c")
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	if workerID.WorkerName != "worker-1" || functionName != "a/b/c" {
+		t.Fatalf("Expected worker-1 and a/b/c, actual: %+v, %q", workerID, functionName)
+	}
+}
+
+func TestParseWorkerURIErrors(t *testing.T) {
+	tests := map[string]string{
+		"urn:component:4bcc2fb0-31c5-4fb1-9e6d-4f2ae0e5c8e6":                 "expected urn:worker: prefix",
+		"urn:worker:urn:uuid:4bcc2fb0-31c5-4fb1-9e6d-4f2ae0e5c8e6":           "expected urn:worker:<component-id>/<worker-name>[/<function-name>]",
+		"urn:worker:component-one/worker-1":                                  "invalid component id",
+		"urn:worker:urn:uuid:4bcc2fb0-31c5-4fb1-9e6d-4f2ae0e5c8e6/":          "invalid worker name: empty",
+		"urn:worker:urn:uuid:4bcc2fb0-31c5-4fb1-9e6d-4f2ae0e5c8e6/a b":       `invalid worker name: illegal character ' ' at 1`,
+		"urn:worker:urn:uuid:4bcc2fb0-31c5-4fb1-9e6d-4f2ae0e5c8e6/worker-1/": "empty function name",
+	}
+	for value, expected := range tests {
+		_, _, err := ParseWorkerURI(value)
+		if err == nil || !strings.Contains(err.Error(), expected) {
+			t.Fatalf("Expected error for %q containing %q, actual: %v", value, expected, err)
+		}
+	}
+}
+
+func TestValidateWorkerName(t *testing.T) {
+	for _, workerName := range []string{"w", "worker-1", "a?b", "név", strings.Repeat("a", MaxWorkerNameLength)} {
+		if err := ValidateWorkerName(workerName); err != nil {
+			t.Fatalf("%+v", err)
+		}
+	}
+
+	tests := map[string]string{
+		"": "empty",
+		strings.Repeat("a", MaxWorkerNameLength+1): "longer than 512 bytes",
+		"a\tb":     `illegal character '\t' at 1`,
+		"a\x00":    `illegal character '\x00' at 1`,
+		"a\xffb":   "invalid UTF-8",
+		"worker 1": `illegal character ' ' at 6`,
+		"a/b":      `illegal character '/' at 1`,
+	}
+	for workerName, expected := range tests {
+		err := ValidateWorkerName(workerName)
+		if err == nil || !strings.Contains(err.Error(), expected) {
+			t.Fatalf("Expected error for %q containing %q, actual: %v", workerName, expected, err)
+		}
+	}
+}
+
+func TestComponentWorkerURIValidatesWorkerName(t *testing.T) {
+	t.Setenv(ComponentOneIDEnvVar, uuid.UUID(testComponentID).String())
+
+	_, err := ComponentOneWorkerURI("worker 1")
+	if err == nil || !strings.Contains(err.Error(), "invalid worker name") {
+		t.Fatalf("Expected invalid worker name error, actual: %v", err)
+	}
+}
@@ -0,0 +1,99 @@
+// Code generated by the generateClients magefile command. DO NOT EDIT.
+
+// Package componentthree is a typed RPC client for the component-three-api of component-three workers
+package componentthree
+
+import (
+	"context"
+	"fmt"
+
+	"golem-go-project/lib/cfg"
+	"golem-go-project/lib/host"
+	"golem-go-project/lib/rpc"
+)
+
+// Client invokes the functions of a component-three worker
+type Client struct {
+	// workerName is the name of the worker, or the routing key of routed clients
+	workerName string
+	routed     bool
+}
+
+// Worker returns a client for the component-three worker with the name
+func Worker(workerName string) *Client {
+	return &Client{workerName: workerName}
+}
+
+// Routed returns a client for the component-three worker selected by its routing for the key, see cfg.ComponentRouting
+func Routed(key string) *Client {
+	return &Client{workerName: key, routed: true}
+}
+
+// Add invokes golem:component-three/component-three-api.{add}
+func (c *Client) Add(ctx context.Context, value uint64) error {
+	_, err := c.invoke(ctx, "golem:component-three/component-three-api.{add}", value)
+	return err
+}
+
+// AddFireAndForget invokes golem:component-three/component-three-api.{add} without waiting for it to complete, delivery errors are logged
+func (c *Client) AddFireAndForget(ctx context.Context, value uint64) {
+	c.invokeFireAndForget(ctx, "golem:component-three/component-three-api.{add}", value)
+}
+
+// AddAsync starts invoking golem:component-three/component-three-api.{add}, see rpc.AwaitAll
+func (c *Client) AddAsync(ctx context.Context, value uint64) *rpc.Future {
+	return c.invokeAsync(ctx, "golem:component-three/component-three-api.{add}", value)
+}
+
+// Get invokes golem:component-three/component-three-api.{get}
+func (c *Client) Get(ctx context.Context) (uint64, error) {
+	results, err := c.invoke(ctx, "golem:component-three/component-three-api.{get}")
+	if err != nil {
+		return 0, err
+	}
+	return rpc.Result[uint64](results, 0)
+}
+
+// GetAsync starts invoking golem:component-three/component-three-api.{get}, see rpc.AwaitAll
+func (c *Client) GetAsync(ctx context.Context) *rpc.Future {
+	return c.invokeAsync(ctx, "golem:component-three/component-three-api.{get}")
+}
+
+func (c *Client) workerURI() (cfg.URI, error) {
+	if c.routed {
+		return cfg.ComponentThreeRoutedWorkerURI(c.workerName)
+	}
+	return cfg.ComponentThreeWorkerURI(c.workerName)
+}
+
+func (c *Client) invoke(ctx context.Context, functionName string, params ...any) ([]any, error) {
+	workerURI, err := c.workerURI()
+	if err != nil {
+		return nil, fmt.Errorf("componentthree: %w", err)
+	}
+	results, err := rpc.InvokeAndAwait(ctx, workerURI, functionName, params...)
+	if err != nil {
+		return nil, fmt.Errorf("componentthree: %s failed for %s, %w", functionName, c.workerName, err)
+	}
+	return results, nil
+}
+
+func (c *Client) invokeAsync(ctx context.Context, functionName string, params ...any) *rpc.Future {
+	workerURI, err := c.workerURI()
+	if err != nil {
+		return rpc.FailedFuture(functionName, fmt.Errorf("componentthree: %w", err))
+	}
+	return rpc.AsyncInvokeAndAwait(ctx, workerURI, functionName, params...).WrapErr(func(err error) error {
+		return fmt.Errorf("componentthree: %s failed for %s, %w", functionName, c.workerName, err)
+	})
+}
+
+func (c *Client) invokeFireAndForget(ctx context.Context, functionName string, params ...any) {
+	workerURI, err := c.workerURI()
+	if err == nil {
+		err = rpc.Invoke(ctx, workerURI, functionName, params...)
+	}
+	if err != nil {
+		host.Log(host.LogLevelError, "componentthree", fmt.Sprintf("%s failed for %s, %s", functionName, c.workerName, err))
+	}
+}
@@ -0,0 +1,210 @@
+// Package discovery lists the workers of components through golem:api's get-workers resource, e.g. for enumerating
+// the peers of a worker for broadcasts or aggregations. Enumerating workers is a slow operation on the executor, so it
+// should not be used on the hot path of invocations.
+package discovery
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+
+	"golem-go-project/lib/cfg"
+)
+
+// Status is the status of a worker
+type Status int
+
+const (
+	// StatusRunning is the status of workers running an invoked function
+	StatusRunning Status = iota
+	// StatusIdle is the status of workers ready to run an invoked function
+	StatusIdle
+	// StatusSuspended is the status of workers waiting for something, e.g. sleeping or waiting for a promise
+	StatusSuspended
+	// StatusInterrupted is the status of workers with an interrupted invocation, which will be resumed
+	StatusInterrupted
+	// StatusRetrying is the status of workers with a failed invocation, which will be retried
+	StatusRetrying
+	// StatusFailed is the status of workers with a failed invocation, which can no longer be used
+	StatusFailed
+	// StatusExited is the status of workers which exited, and can no longer be invoked
+	StatusExited
+)
+
+var statusNames = []string{"running", "idle", "suspended", "interrupted", "retrying", "failed", "exited"}
+
+func (s Status) String() string {
+	if s < 0 || int(s) >= len(statusNames) {
+		return fmt.Sprintf("status(%d)", int(s))
+	}
+	return statusNames[s]
+}
+
+// Comparator compares status and version values of workers
+type Comparator int
+
+const (
+	Equal Comparator = iota
+	NotEqual
+	GreaterEqual
+	Greater
+	LessEqual
+	Less
+)
+
+// StringComparator compares name and env var values of workers, Like and NotLike match substrings
+type StringComparator int
+
+const (
+	StringEqual StringComparator = iota
+	StringNotEqual
+	Like
+	NotLike
+)
+
+// Filter is a condition on a property of workers, created with Name, Status, Version or Env
+type Filter struct {
+	property filterProperty
+
+	comparator       Comparator
+	stringComparator StringComparator
+
+	envVar  string
+	value   string
+	status  Status
+	version uint64
+}
+
+type filterProperty int
+
+const (
+	filterName filterProperty = iota
+	filterStatus
+	filterVersion
+	filterEnv
+)
+
+// Name selects the workers by name
+func Name(comparator StringComparator, name string) Filter {
+	return Filter{property: filterName, stringComparator: comparator, value: name}
+}
+
+// StatusIs selects the workers by status, e.g. StatusIs(NotEqual, StatusFailed)
+func StatusIs(comparator Comparator, status Status) Filter {
+	return Filter{property: filterStatus, comparator: comparator, status: status}
+}
+
+// Version selects the workers by component version
+func Version(comparator Comparator, version uint64) Filter {
+	return Filter{property: filterVersion, comparator: comparator, version: version}
+}
+
+// Env selects the workers by the value of an env var
+func Env(envVar string, comparator StringComparator, value string) Filter {
+	return Filter{property: filterEnv, stringComparator: comparator, envVar: envVar, value: value}
+}
+
+// Worker is the metadata of a worker
+type Worker struct {
+	ID               cfg.WorkerID
+	Args             []string
+	Env              map[string]string
+	Status           Status
+	ComponentVersion uint64
+	RetryCount       uint64
+}
+
+// Query selects the workers of a component
+type Query struct {
+	ComponentID cfg.ComponentID
+	// AnyOf are groups of filters, a worker is selected if it matches all the filters of any of the groups, and all
+	// workers are selected if there are no groups
+	AnyOf [][]Filter
+	// Precise requests the up-to-date metadata of the workers, which is slower
+	Precise bool
+}
+
+// List returns the workers of the component matching all the filters
+func List(ctx context.Context, componentID cfg.ComponentID, filters ...Filter) ([]Worker, error) {
+	query := Query{ComponentID: componentID}
+	if len(filters) > 0 {
+		query.AnyOf = [][]Filter{filters}
+	}
+	return query.List(ctx)
+}
+
+// ListComponent returns the workers of the component matching all the filters, the id of the component is read with
+// cfg.ComponentIDFromEnv from its env var, e.g. COMPONENT_THREE_ID for component-three
+func ListComponent(ctx context.Context, componentName string, filters ...Filter) ([]Worker, error) {
+	envVar, ok := cfg.ComponentIDEnvVars[componentName]
+	if !ok {
+		return nil, fmt.Errorf("discovery: unknown component: %s", componentName)
+	}
+	componentID, err := cfg.ComponentIDFromEnv(envVar)
+	if err != nil {
+		return nil, err
+	}
+	return List(ctx, componentID, filters...)
+}
+
+// List returns the workers selected by the query, reading all the pages
+func (q Query) List(ctx context.Context) ([]Worker, error) {
+	iterator := q.Iterate()
+	defer iterator.Close()
+
+	var workers []Worker
+	for {
+		if err := ctx.Err(); err != nil {
+			return nil, fmt.Errorf("discovery: list workers failed for %s, %w", q.componentIDString(), err)
+		}
+		page, ok := iterator.Next()
+		if !ok {
+			return workers, nil
+		}
+		workers = append(workers, page...)
+	}
+}
+
+// Iterate returns an iterator over the pages of workers selected by the query, which must be closed
+func (q Query) Iterate() *Iterator {
+	return &Iterator{pages: newPages(q)}
+}
+
+func (q Query) componentIDString() string {
+	return uuid.UUID(q.ComponentID).String()
+}
+
+// Iterator iterates the pages of workers returned by get-workers
+type Iterator struct {
+	pages  pages
+	closed bool
+}
+
+// pages returns the pages of a get-workers resource
+type pages interface {
+	// next returns the next page, and false if there are no more pages
+	next() ([]Worker, bool)
+	close()
+}
+
+// Next returns the next page of workers, and false if there are no more pages
+func (it *Iterator) Next() ([]Worker, bool) {
+	if it.closed {
+		return nil, false
+	}
+	page, ok := it.pages.next()
+	if !ok {
+		it.Close()
+	}
+	return page, ok
+}
+
+// Close releases the get-workers resource, it is also closed after the last page
+func (it *Iterator) Close() {
+	if it.closed {
+		return
+	}
+	it.closed = true
+	it.pages.close()
+}
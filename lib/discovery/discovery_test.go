@@ -0,0 +1,160 @@
+//go:build hosttest
+
+package discovery
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"golem-go-project/lib/cfg"
+)
+
+var (
+	testComponentID  = cfg.ComponentID(uuid.MustParse("4bcc2fb0-31c5-4fb1-9e6d-4f2ae0e5c8e6"))
+	otherComponentID = cfg.ComponentID(uuid.MustParse("9a1cb4a4-4bf0-4ae5-9c1b-7a3c9e4fb0d2"))
+)
+
+func addTestWorkers(t *testing.T) {
+	t.Cleanup(ResetFakeWorkers)
+	for i := 0; i < 25; i++ {
+		worker := Worker{
+			ID:               cfg.WorkerID{ComponentID: testComponentID, WorkerName: fmt.Sprintf("worker-%d", i)},
+			Env:              map[string]string{"REGION": "eu"},
+			Status:           StatusIdle,
+			ComponentVersion: uint64(i % 3),
+		}
+		if i%5 == 0 {
+			worker.Status = StatusFailed
+		}
+		if i%2 == 0 {
+			worker.Env["REGION"] = "us"
+		}
+		AddFakeWorker(worker)
+	}
+	AddFakeWorker(Worker{ID: cfg.WorkerID{ComponentID: otherComponentID, WorkerName: "worker-0"}})
+}
+
+func workerNames(workers []Worker) []string {
+	names := make([]string, len(workers))
+	for i, worker := range workers {
+		names[i] = worker.ID.WorkerName
+	}
+	return names
+}
+
+func TestList(t *testing.T) {
+	addTestWorkers(t)
+
+	workers, err := List(context.Background(), testComponentID)
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	if len(workers) != 25 {
+		t.Fatalf("Expected 25 workers of all pages, actual: %d", len(workers))
+	}
+
+	workers, err = List(
+		context.Background(),
+		testComponentID,
+		Name(Like, "worker-1"),
+		StatusIs(NotEqual, StatusFailed),
+		Env("REGION", StringEqual, "eu"),
+		Version(GreaterEqual, 1),
+	)
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	expected := []string{"worker-1", "worker-11", "worker-13", "worker-17", "worker-19"}
+	if actual := workerNames(workers); !reflect.DeepEqual(actual, expected) {
+		t.Fatalf("Expected: %v, actual: %v", expected, actual)
+	}
+}
+
+func TestQueryAnyOf(t *testing.T) {
+	addTestWorkers(t)
+
+	query := Query{
+		ComponentID: testComponentID,
+		AnyOf: [][]Filter{
+			{Name(StringEqual, "worker-3")},
+			{StatusIs(Equal, StatusFailed), Version(Less, 1)},
+		},
+	}
+	workers, err := query.List(context.Background())
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	expected := []string{"worker-0", "worker-3", "worker-15"}
+	if actual := workerNames(workers); !reflect.DeepEqual(actual, expected) {
+		t.Fatalf("Expected: %v, actual: %v", expected, actual)
+	}
+}
+
+func TestIterate(t *testing.T) {
+	addTestWorkers(t)
+
+	iterator := Query{ComponentID: testComponentID}.Iterate()
+	defer iterator.Close()
+
+	var pageSizes []int
+	for {
+		page, ok := iterator.Next()
+		if !ok {
+			break
+		}
+		pageSizes = append(pageSizes, len(page))
+	}
+	if expected := []int{10, 10, 5}; !reflect.DeepEqual(pageSizes, expected) {
+		t.Fatalf("Expected page sizes: %v, actual: %v", expected, pageSizes)
+	}
+	if _, ok := iterator.Next(); ok {
+		t.Fatalf("Expected no more pages")
+	}
+}
+
+func TestListCanceled(t *testing.T) {
+	addTestWorkers(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err := List(ctx, testComponentID)
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("Expected context.Canceled, actual: %+v", err)
+	}
+}
+
+func TestListComponent(t *testing.T) {
+	addTestWorkers(t)
+	t.Setenv(cfg.ComponentThreeIDEnvVar, "urn:component:"+uuid.UUID(otherComponentID).String())
+
+	workers, err := ListComponent(context.Background(), "component-three")
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	if actual := workerNames(workers); !reflect.DeepEqual(actual, []string{"worker-0"}) {
+		t.Fatalf("Expected the worker of the other component, actual: %v", actual)
+	}
+
+	t.Setenv(cfg.ComponentThreeIDEnvVar, "")
+	_, err = ListComponent(context.Background(), "component-three")
+	var cfgErr *cfg.Error
+	if !errors.As(err, &cfgErr) {
+		t.Fatalf("Expected config error, actual: %+v", err)
+	}
+
+	_, err = ListComponent(context.Background(), "component-five")
+	if err == nil || err.Error() != "discovery: unknown component: component-five" {
+		t.Fatalf("Expected unknown component error, actual: %+v", err)
+	}
+}
+
+func TestStatusString(t *testing.T) {
+	if StatusSuspended.String() != "suspended" || Status(42).String() != "status(42)" {
+		t.Fatalf("Unexpected status names: %s, %s", StatusSuspended, Status(42))
+	}
+}
@@ -0,0 +1,86 @@
+//go:build !hosttest
+
+package discovery
+
+import (
+	"github.com/golemcloud/golem-go/binding"
+	"github.com/golemcloud/golem-go/golemhost"
+)
+
+// getWorkersPages returns the pages of the get-workers resource
+type getWorkersPages struct {
+	getWorkers binding.GolemApi0_2_0_HostGetWorkers
+}
+
+// newPages creates the get-workers resource of the query
+func newPages(q Query) pages {
+	// NOTE: golemhost.GetWorkers drops the filter and reads all the pages at once, so the binding is used directly
+	filter := binding.None[binding.GolemApi0_2_0_HostWorkerAnyFilter]()
+	if len(q.AnyOf) > 0 {
+		filter = binding.Some(anyFilter(q.AnyOf).ToBinding())
+	}
+	return &getWorkersPages{
+		getWorkers: binding.NewGetWorkers(golemhost.ComponentID(q.ComponentID).ToBinding(), filter, q.Precise),
+	}
+}
+
+func (p *getWorkersPages) next() ([]Worker, bool) {
+	page := p.getWorkers.GetNext()
+	if page.IsNone() {
+		return nil, false
+	}
+	workers := make([]Worker, len(page.Unwrap()))
+	for i, metadata := range page.Unwrap() {
+		workers[i] = newWorker(golemhost.NewWorkerMetadata(metadata))
+	}
+	return workers, true
+}
+
+func (p *getWorkersPages) close() {
+	p.getWorkers.Drop()
+}
+
+func newWorker(metadata golemhost.WorkerMetadata) Worker {
+	env := make(map[string]string, len(metadata.Env))
+	for _, envVar := range metadata.Env {
+		env[envVar.Name] = envVar.Value
+	}
+	return Worker{
+		ID:               metadata.WorkerId,
+		Args:             metadata.Args,
+		Env:              env,
+		Status:           Status(metadata.Status),
+		ComponentVersion: metadata.ComponentVersion,
+		RetryCount:       metadata.RetryCount,
+	}
+}
+
+func anyFilter(anyOf [][]Filter) golemhost.WorkerAnyFilter {
+	filter := golemhost.WorkerAnyFilter{Filters: make([]golemhost.WorkerAllFilter, len(anyOf))}
+	for i, allOf := range anyOf {
+		filter.Filters[i].Filters = make([]golemhost.WorkerFilter, len(allOf))
+		for j, f := range allOf {
+			filter.Filters[i].Filters[j] = workerFilter(f)
+		}
+	}
+	return filter
+}
+
+func workerFilter(f Filter) golemhost.WorkerFilter {
+	stringComparator := golemhost.StringFilterComparator(f.stringComparator)
+	comparator := golemhost.FilterComparator(f.comparator)
+	switch f.property {
+	case filterName:
+		return golemhost.WorkerFilter{Name: &f.value, NameComparator: stringComparator}
+	case filterStatus:
+		status := golemhost.WorkerStatus(f.status)
+		return golemhost.WorkerFilter{Status: &status, StatusComparator: comparator}
+	case filterVersion:
+		return golemhost.WorkerFilter{Version: &f.version, VersionComparator: comparator}
+	default:
+		return golemhost.WorkerFilter{
+			Env:           &golemhost.WorkerEnvFilter{Name: f.envVar, Value: f.value},
+			EnvComparator: stringComparator,
+		}
+	}
+}
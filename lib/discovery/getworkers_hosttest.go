@@ -0,0 +1,133 @@
+//go:build hosttest
+
+package discovery
+
+import (
+	"strings"
+	"sync"
+
+	"github.com/google/uuid"
+)
+
+// fakePageSize is the number of workers in the pages of the fake get-workers
+const fakePageSize = 10
+
+var (
+	fakeWorkers   []Worker
+	fakeWorkersMu sync.Mutex
+)
+
+// AddFakeWorker adds the fake worker, which is listed if it matches the query
+func AddFakeWorker(worker Worker) {
+	fakeWorkersMu.Lock()
+	defer fakeWorkersMu.Unlock()
+	fakeWorkers = append(fakeWorkers, worker)
+}
+
+// ResetFakeWorkers removes all the fake workers
+func ResetFakeWorkers() {
+	fakeWorkersMu.Lock()
+	defer fakeWorkersMu.Unlock()
+	fakeWorkers = nil
+}
+
+// fakePages returns the matching fake workers in pages of fakePageSize
+type fakePages struct {
+	workers []Worker
+}
+
+// newPages selects the fake workers matching the query
+func newPages(q Query) pages {
+	fakeWorkersMu.Lock()
+	defer fakeWorkersMu.Unlock()
+
+	var workers []Worker
+	for _, worker := range fakeWorkers {
+		if uuid.UUID(worker.ID.ComponentID) == uuid.UUID(q.ComponentID) && matchesAny(worker, q.AnyOf) {
+			workers = append(workers, worker)
+		}
+	}
+	return &fakePages{workers: workers}
+}
+
+func (p *fakePages) next() ([]Worker, bool) {
+	if len(p.workers) == 0 {
+		return nil, false
+	}
+	size := fakePageSize
+	if len(p.workers) < size {
+		size = len(p.workers)
+	}
+	page := p.workers[:size]
+	p.workers = p.workers[size:]
+	return page, true
+}
+
+func (p *fakePages) close() {
+	p.workers = nil
+}
+
+func matchesAny(worker Worker, anyOf [][]Filter) bool {
+	if len(anyOf) == 0 {
+		return true
+	}
+	for _, allOf := range anyOf {
+		if matchesAll(worker, allOf) {
+			return true
+		}
+	}
+	return false
+}
+
+func matchesAll(worker Worker, allOf []Filter) bool {
+	for _, filter := range allOf {
+		if !matches(worker, filter) {
+			return false
+		}
+	}
+	return true
+}
+
+func matches(worker Worker, filter Filter) bool {
+	switch filter.property {
+	case filterName:
+		return compareStrings(worker.ID.WorkerName, filter.stringComparator, filter.value)
+	case filterStatus:
+		return compare(uint64(worker.Status), filter.comparator, uint64(filter.status))
+	case filterVersion:
+		return compare(worker.ComponentVersion, filter.comparator, filter.version)
+	default:
+		value, ok := worker.Env[filter.envVar]
+		return ok && compareStrings(value, filter.stringComparator, filter.value)
+	}
+}
+
+func compare(value uint64, comparator Comparator, filterValue uint64) bool {
+	switch comparator {
+	case Equal:
+		return value == filterValue
+	case NotEqual:
+		return value != filterValue
+	case GreaterEqual:
+		return value >= filterValue
+	case Greater:
+		return value > filterValue
+	case LessEqual:
+		return value <= filterValue
+	default:
+		return value < filterValue
+	}
+}
+
+func compareStrings(value string, comparator StringComparator, filterValue string) bool {
+	switch comparator {
+	case StringEqual:
+		return value == filterValue
+	case StringNotEqual:
+		return value != filterValue
+	case Like:
+		return strings.Contains(value, filterValue)
+	default:
+		return !strings.Contains(value, filterValue)
+	}
+}
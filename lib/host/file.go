@@ -0,0 +1,89 @@
+//go:build !hosttest
+
+package host
+
+import (
+	"fmt"
+	"io/fs"
+	"path"
+	"strings"
+
+	"github.com/golemcloud/golem-go/binding"
+)
+
+// readFileChunkSize is the number of bytes read at once
+const readFileChunkSize = 64 * 1024
+
+// ReadFile reads the file with the absolute path from the preopened directories of the worker, using
+// wasi:filesystem/preopens. The preopened directory with the longest matching path is used. Missing files are reported
+// with fs.ErrNotExist.
+func ReadFile(filePath string) ([]byte, error) {
+	filePath = path.Clean(filePath)
+
+	preopens := binding.WasiFilesystem0_2_0_PreopensGetDirectories()
+	defer func() {
+		for _, preopen := range preopens {
+			preopen.F0.Drop()
+		}
+	}()
+
+	var dir binding.WasiFilesystem0_2_0_TypesDescriptor
+	var relPath string
+	found := false
+	for _, preopen := range preopens {
+		rel, ok := relativeToDir(filePath, path.Clean(preopen.F1))
+		if ok && (!found || len(rel) < len(relPath)) {
+			dir, relPath, found = preopen.F0, rel, true
+		}
+	}
+	if !found {
+		return nil, fmt.Errorf("host: read file failed for %s, no preopened directory contains it, %w", filePath, fs.ErrNotExist)
+	}
+
+	openResult := dir.OpenAt(
+		binding.WasiFilesystem0_2_0_TypesPathFlags_SymlinkFollow,
+		relPath,
+		0,
+		binding.WasiFilesystem0_2_0_TypesDescriptorFlags_Read,
+	)
+	if openResult.IsErr() {
+		return nil, fmt.Errorf("host: open failed for %s, %w", filePath, fileError(openResult.UnwrapErr()))
+	}
+	file := openResult.Unwrap()
+	defer file.Drop()
+
+	var data []byte
+	for {
+		readResult := file.Read(readFileChunkSize, uint64(len(data)))
+		if readResult.IsErr() {
+			return nil, fmt.Errorf("host: read failed for %s, %w", filePath, fileError(readResult.UnwrapErr()))
+		}
+		chunk := readResult.Unwrap()
+		data = append(data, chunk.F0...)
+		if chunk.F1 || len(chunk.F0) == 0 {
+			return data, nil
+		}
+	}
+}
+
+// relativeToDir returns the path of filePath relative to dir, if it is inside dir
+func relativeToDir(filePath, dir string) (string, bool) {
+	if dir == "." || dir == "/" {
+		return strings.TrimPrefix(filePath, "/"), true
+	}
+	if !strings.HasPrefix(filePath, dir+"/") {
+		return "", false
+	}
+	return filePath[len(dir)+1:], true
+}
+
+func fileError(errorCode binding.WasiFilesystem0_2_0_TypesErrorCode) error {
+	switch errorCode.Kind() {
+	case binding.WasiFilesystem0_2_0_TypesErrorCodeKindNoEntry:
+		return fs.ErrNotExist
+	case binding.WasiFilesystem0_2_0_TypesErrorCodeKindAccess, binding.WasiFilesystem0_2_0_TypesErrorCodeKindNotPermitted:
+		return fs.ErrPermission
+	default:
+		return fmt.Errorf("wasi:filesystem error code %d", errorCode.Kind())
+	}
+}
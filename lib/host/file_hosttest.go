@@ -0,0 +1,40 @@
+//go:build hosttest
+
+package host
+
+import (
+	"fmt"
+	"io/fs"
+	"path"
+	"sync"
+)
+
+var (
+	fakeFiles   = map[string][]byte{}
+	fakeFilesMu sync.Mutex
+)
+
+// ReadFile reads the fake file with the path, see SetFakeFile
+func ReadFile(filePath string) ([]byte, error) {
+	fakeFilesMu.Lock()
+	defer fakeFilesMu.Unlock()
+	data, ok := fakeFiles[path.Clean(filePath)]
+	if !ok {
+		return nil, fmt.Errorf("host: read file failed for %s, %w", filePath, fs.ErrNotExist)
+	}
+	return append([]byte(nil), data...), nil
+}
+
+// SetFakeFile sets the content of the fake file with the path, like a file in a preopened directory
+func SetFakeFile(filePath string, data []byte) {
+	fakeFilesMu.Lock()
+	defer fakeFilesMu.Unlock()
+	fakeFiles[path.Clean(filePath)] = append([]byte(nil), data...)
+}
+
+// ResetFakeFiles removes all the fake files
+func ResetFakeFiles() {
+	fakeFilesMu.Lock()
+	defer fakeFilesMu.Unlock()
+	fakeFiles = map[string][]byte{}
+}
@@ -0,0 +1,14 @@
+//go:build !hosttest
+
+// Package host wraps the golem host calls used by the components, so they can be replaced with in-memory fakes
+// when running unit tests on the host with the hosttest build tag.
+package host
+
+import (
+	"github.com/golemcloud/golem-go/golemhost"
+)
+
+// SelfWorkerName returns the name of the current worker
+func SelfWorkerName() string {
+	return golemhost.GetSelfMetadata().WorkerId.WorkerName
+}
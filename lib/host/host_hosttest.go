@@ -0,0 +1,60 @@
+//go:build hosttest
+
+// Package host wraps the golem host calls used by the components, so they can be replaced with in-memory fakes
+// when running unit tests on the host with the hosttest build tag.
+package host
+
+import (
+	"sync"
+)
+
+var (
+	selfWorkerName   = "hosttest-worker"
+	selfWorkerNameMu sync.Mutex
+)
+
+// SelfWorkerName returns the fake name of the current worker
+func SelfWorkerName() string {
+	selfWorkerNameMu.Lock()
+	defer selfWorkerNameMu.Unlock()
+	return selfWorkerName
+}
+
+// SetSelfWorkerName sets the fake name of the current worker
+func SetSelfWorkerName(workerName string) {
+	selfWorkerNameMu.Lock()
+	defer selfWorkerNameMu.Unlock()
+	selfWorkerName = workerName
+}
+
+// Workers is an in-memory registry of fake RPC targets by worker URI
+type Workers[T any] struct {
+	mu      sync.Mutex
+	workers map[string]T
+}
+
+func NewWorkers[T any]() *Workers[T] {
+	return &Workers[T]{workers: make(map[string]T)}
+}
+
+// Register registers the fake worker for the worker URI
+func (w *Workers[T]) Register(uri string, worker T) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.workers[uri] = worker
+}
+
+// Get returns the fake worker registered for the worker URI, or false if there is none
+func (w *Workers[T]) Get(uri string) (T, bool) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	worker, ok := w.workers[uri]
+	return worker, ok
+}
+
+// Reset removes all the registered fake workers
+func (w *Workers[T]) Reset() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.workers = make(map[string]T)
+}
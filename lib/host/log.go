@@ -0,0 +1,29 @@
+//go:build !hosttest
+
+package host
+
+import (
+	"github.com/golemcloud/golem-go/binding"
+)
+
+// Log logs the message through wasi:logging, the context is usually the name of the package or operation
+func Log(level LogLevel, context, message string) {
+	binding.WasiLoggingLoggingLog(bindingLogLevel(level), context, message)
+}
+
+func bindingLogLevel(level LogLevel) binding.WasiLoggingLoggingLevel {
+	switch level {
+	case LogLevelTrace:
+		return binding.WasiLoggingLoggingLevelTrace()
+	case LogLevelDebug:
+		return binding.WasiLoggingLoggingLevelDebug()
+	case LogLevelInfo:
+		return binding.WasiLoggingLoggingLevelInfo()
+	case LogLevelWarn:
+		return binding.WasiLoggingLoggingLevelWarn()
+	case LogLevelError:
+		return binding.WasiLoggingLoggingLevelError()
+	default:
+		return binding.WasiLoggingLoggingLevelCritical()
+	}
+}
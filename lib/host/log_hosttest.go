@@ -0,0 +1,40 @@
+//go:build hosttest
+
+package host
+
+import (
+	"sync"
+)
+
+// LogEntry is a message logged with Log
+type LogEntry struct {
+	Level   LogLevel
+	Context string
+	Message string
+}
+
+var (
+	logEntries   []LogEntry
+	logEntriesMu sync.Mutex
+)
+
+// Log records the message, see Logs
+func Log(level LogLevel, context, message string) {
+	logEntriesMu.Lock()
+	defer logEntriesMu.Unlock()
+	logEntries = append(logEntries, LogEntry{Level: level, Context: context, Message: message})
+}
+
+// Logs returns the recorded log entries
+func Logs() []LogEntry {
+	logEntriesMu.Lock()
+	defer logEntriesMu.Unlock()
+	return append([]LogEntry(nil), logEntries...)
+}
+
+// ResetLogs removes the recorded log entries
+func ResetLogs() {
+	logEntriesMu.Lock()
+	defer logEntriesMu.Unlock()
+	logEntries = nil
+}
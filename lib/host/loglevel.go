@@ -0,0 +1,36 @@
+package host
+
+import (
+	"fmt"
+)
+
+// LogLevel is the level of a log message, mirroring wasi:logging's level
+type LogLevel int
+
+const (
+	LogLevelTrace LogLevel = iota
+	LogLevelDebug
+	LogLevelInfo
+	LogLevelWarn
+	LogLevelError
+	LogLevelCritical
+)
+
+func (l LogLevel) String() string {
+	switch l {
+	case LogLevelTrace:
+		return "trace"
+	case LogLevelDebug:
+		return "debug"
+	case LogLevelInfo:
+		return "info"
+	case LogLevelWarn:
+		return "warn"
+	case LogLevelError:
+		return "error"
+	case LogLevelCritical:
+		return "critical"
+	default:
+		return fmt.Sprintf("LogLevel(%d)", int(l))
+	}
+}
@@ -0,0 +1,122 @@
+//go:build !hosttest
+
+package rpc
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/golemcloud/golem-go/binding"
+
+	"golem-go-project/lib/cfg"
+	"golem-go-project/lib/witvalue"
+)
+
+type pendingInvocation struct {
+	pollable binding.WasiIo0_2_0_PollPollable
+	// get returns the result once ready, or false if it is not ready yet
+	get  func() (witvalue.Value, bool, error)
+	done func()
+}
+
+func (p *pendingInvocation) drop() {
+	p.pollable.Drop()
+	p.done()
+}
+
+func asyncInvokeAndAwait(workerURI cfg.URI, functionName string, params []witvalue.Value) *pendingInvocation {
+	wasmRpc := binding.NewWasmRpc(workerURI)
+	futureResult := wasmRpc.AsyncInvokeAndAwait(functionName, bindingParams(params))
+
+	return &pendingInvocation{
+		pollable: futureResult.Subscribe(),
+		get: func() (witvalue.Value, bool, error) {
+			result := futureResult.Get()
+			if result.IsNone() {
+				return witvalue.Value{}, false, nil
+			}
+			value, err := invokeResult(functionName, result.Unwrap())
+			return value, true, err
+		},
+		done: func() {
+			futureResult.Drop()
+			wasmRpc.Drop()
+		},
+	}
+}
+
+// FromPollable wraps an asynchronous result of a generated stub (e.g. the future-get-result of component-two's stub),
+// so it can be awaited with AwaitAll. The pollable is the handle returned by its subscribe method, which is dropped
+// by the future, get returns the results once the pollable is ready, and drop releases the stub's resources.
+func FromPollable(functionName string, pollable int32, get func() ([]any, error), drop func()) *Future {
+	return &Future{
+		functionName: functionName,
+		pending: &pendingInvocation{
+			pollable: binding.WasiIo0_2_0_PollPollable(pollable),
+			get: func() (witvalue.Value, bool, error) {
+				results, err := get()
+				if err != nil {
+					return witvalue.Value{}, true, err
+				}
+				value, err := witvalue.Marshal(witvalue.Tuple(results))
+				if err != nil {
+					return witvalue.Value{}, true, fmt.Errorf("invoke %s: %w", functionName, err)
+				}
+				return value, true, nil
+			},
+			done: drop,
+		},
+	}
+}
+
+// AwaitAll waits for all the futures by polling their pollables together, so the invocations run concurrently,
+// and the latency is the maximum of them instead of their sum. It returns the joined errors of the futures, or the
+// error of the context, if its deadline is exceeded before all of them complete.
+func AwaitAll(ctx context.Context, futures ...*Future) error {
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		var pending []*Future
+		var pollables []binding.WasiIo0_2_0_PollPollable
+		for _, future := range futures {
+			if !future.Done() {
+				pending = append(pending, future)
+				pollables = append(pollables, future.pending.pollable)
+			}
+		}
+		if len(pending) == 0 {
+			return futuresErr(futures)
+		}
+
+		var timeout binding.WasiIo0_2_0_PollPollable
+		deadline, hasDeadline := ctx.Deadline()
+		if hasDeadline {
+			remaining := time.Until(deadline)
+			if remaining < 0 {
+				remaining = 0
+			}
+			timeout = binding.WasiClocks0_2_0_MonotonicClockSubscribeDuration(uint64(remaining))
+			pollables = append(pollables, timeout)
+		}
+
+		for _, index := range binding.WasiIo0_2_0_PollPoll(pollables) {
+			if int(index) >= len(pending) {
+				continue
+			}
+			value, ok, err := pending[index].pending.get()
+			if ok {
+				pending[index].complete(value, err)
+			}
+		}
+
+		if hasDeadline {
+			timeout.Drop()
+			if !time.Now().Before(deadline) {
+				return fmt.Errorf("await all: %w", context.DeadlineExceeded)
+			}
+		}
+	}
+}
@@ -0,0 +1,43 @@
+//go:build hosttest
+
+package rpc
+
+import (
+	"context"
+
+	"golem-go-project/lib/cfg"
+	"golem-go-project/lib/witvalue"
+)
+
+type pendingInvocation struct {
+	invoke func() (witvalue.Value, error)
+}
+
+func (p *pendingInvocation) drop() {}
+
+// asyncInvokeAndAwait returns the pending invocation of the fake worker registered for the worker URI, the handler
+// of the fake worker is called when the future is awaited
+func asyncInvokeAndAwait(workerURI cfg.URI, functionName string, params []witvalue.Value) *pendingInvocation {
+	handler, err := fakeWorker(workerURI, functionName)
+	return &pendingInvocation{
+		invoke: func() (witvalue.Value, error) {
+			if err != nil {
+				return witvalue.Value{}, err
+			}
+			return fakeInvoke(handler, functionName, params)
+		},
+	}
+}
+
+// AwaitAll calls the handlers of the fake workers for the futures, and returns the joined errors of the futures
+func AwaitAll(ctx context.Context, futures ...*Future) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	for _, future := range futures {
+		if !future.Done() {
+			future.complete(future.pending.invoke())
+		}
+	}
+	return futuresErr(futures)
+}
@@ -0,0 +1,91 @@
+package rpc
+
+import (
+	"errors"
+	"fmt"
+
+	"golem-go-project/lib/cfg"
+	"golem-go-project/lib/witvalue"
+)
+
+// ErrorKind is the kind of an RPC error, mirroring golem:rpc's rpc-error cases, and the config-error case of the error
+// variant returned by the exported functions of the components
+type ErrorKind int
+
+const (
+	ErrorKindProtocolError ErrorKind = iota
+	ErrorKindDenied
+	ErrorKindNotFound
+	ErrorKindRemoteInternalError
+	ErrorKindConfigError
+)
+
+func (k ErrorKind) String() string {
+	switch k {
+	case ErrorKindProtocolError:
+		return "protocol-error"
+	case ErrorKindDenied:
+		return "denied"
+	case ErrorKindNotFound:
+		return "not-found"
+	case ErrorKindRemoteInternalError:
+		return "remote-internal-error"
+	case ErrorKindConfigError:
+		return "config-error"
+	default:
+		return fmt.Sprintf("ErrorKind(%d)", int(k))
+	}
+}
+
+// Error is an rpc-error returned by golem:rpc's wasm-rpc, or the error case returned by a function of another worker.
+// It is marshaled as the error variant of the exported functions of the components:
+//
+//	variant error {
+//	  protocol-error(string),
+//	  denied(string),
+//	  not-found(string),
+//	  remote-internal-error(string),
+//	  config-error(string),
+//	}
+type Error struct {
+	Kind    ErrorKind
+	Message string
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("rpc %s: %s", e.Kind, e.Message)
+}
+
+// MarshalWitVariant implements witvalue.VariantMarshaler
+func (e Error) MarshalWitVariant() (witvalue.Variant, error) {
+	if e.Kind < ErrorKindProtocolError || e.Kind > ErrorKindConfigError {
+		return witvalue.Variant{}, fmt.Errorf("invalid error kind: %s", e.Kind)
+	}
+	return witvalue.Variant{Case: uint32(e.Kind), Payload: e.Message}, nil
+}
+
+// UnmarshalWitVariant implements witvalue.VariantUnmarshaler
+func (e *Error) UnmarshalWitVariant(caseIndex uint32, payload witvalue.Payload) error {
+	kind := ErrorKind(caseIndex)
+	if kind < ErrorKindProtocolError || kind > ErrorKindConfigError {
+		return fmt.Errorf("invalid error case: %d", caseIndex)
+	}
+	e.Kind = kind
+	return payload.Unmarshal(&e.Message)
+}
+
+// AsError converts err to an Error, which can be returned as the error case of an exported function: RPC errors keep
+// their kind, cfg errors are config-errors and other errors are remote-internal-errors. The message is the message of
+// err, so the context added on every worker of the call chain is kept.
+func AsError(err error) *Error {
+	var rpcErr *Error
+	var cfgErr *cfg.Error
+	switch {
+	case errors.As(err, &rpcErr):
+		return &Error{Kind: rpcErr.Kind, Message: err.Error()}
+	case errors.As(err, &cfgErr):
+		return &Error{Kind: ErrorKindConfigError, Message: err.Error()}
+	default:
+		return &Error{Kind: ErrorKindRemoteInternalError, Message: err.Error()}
+	}
+}
@@ -0,0 +1,120 @@
+package rpc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"golem-go-project/lib/witvalue"
+)
+
+// Future is the pending result of an asynchronous invocation, see AsyncInvokeAndAwait and AwaitAll
+type Future struct {
+	functionName string
+	pending      *pendingInvocation
+	value        witvalue.Value
+	err          error
+	// result is set for functions returning result<T, error>, see AsyncInvokeAndAwaitResult
+	result  bool
+	wrapErr func(err error) error
+}
+
+// FailedFuture returns a completed future with the error, e.g. for invocations which could not be started
+func FailedFuture(functionName string, err error) *Future {
+	return &Future{functionName: functionName, err: err}
+}
+
+// WrapErr sets the function adding context to the error of the future
+func (f *Future) WrapErr(wrapErr func(err error) error) *Future {
+	f.wrapErr = wrapErr
+	if f.Done() && f.err != nil {
+		f.err = wrapErr(f.err)
+	}
+	return f
+}
+
+// Done returns whether the result of the future is available
+func (f *Future) Done() bool {
+	return f.pending == nil
+}
+
+// Await waits for the future and returns its results
+func (f *Future) Await(ctx context.Context) ([]any, error) {
+	if err := f.wait(ctx); err != nil {
+		return nil, err
+	}
+	if f.result {
+		var ok any
+		if err := unmarshalResult(f.functionName, f.value, &ok); err != nil {
+			return nil, err
+		}
+		return []any{ok}, nil
+	}
+	return dynamicResults(f.functionName, f.value)
+}
+
+// AwaitInto waits for the future and unmarshals its results into the values pointed to by results
+func (f *Future) AwaitInto(ctx context.Context, results ...any) error {
+	if err := f.wait(ctx); err != nil {
+		return err
+	}
+	if f.result {
+		if len(results) > 1 {
+			return fmt.Errorf("invoke %s: results: expected at most 1 result, got %d", f.functionName, len(results))
+		}
+		var ok any
+		if len(results) == 1 {
+			ok = results[0]
+		}
+		return unmarshalResult(f.functionName, f.value, ok)
+	}
+	return unmarshalResults(f.functionName, f.value, results)
+}
+
+func (f *Future) wait(ctx context.Context) error {
+	err := AwaitAll(ctx, f)
+	if err != nil && !f.Done() {
+		return err
+	}
+	return f.err
+}
+
+// Drop releases the resources of the future without waiting for its result
+func (f *Future) Drop() {
+	if f.pending != nil {
+		f.pending.drop()
+		f.pending = nil
+		f.err = fmt.Errorf("invoke %s: dropped before completion", f.functionName)
+	}
+}
+
+// Await waits for the future and returns its only result unmarshaled to T
+func Await[T any](ctx context.Context, future *Future) (T, error) {
+	var result T
+	err := future.AwaitInto(ctx, &result)
+	return result, err
+}
+
+func (f *Future) complete(value witvalue.Value, err error) {
+	f.pending.drop()
+	f.pending = nil
+	f.value = value
+	f.err = err
+	if err == nil && f.result {
+		f.err = unmarshalResult(f.functionName, value, nil)
+	}
+	if f.err != nil && f.wrapErr != nil {
+		f.err = f.wrapErr(f.err)
+	}
+}
+
+// futuresErr joins the errors of the futures
+func futuresErr(futures []*Future) error {
+	var errs []error
+	for _, future := range futures {
+		if future.err != nil {
+			errs = append(errs, future.err)
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,66 @@
+//go:build !hosttest
+
+package rpc
+
+import (
+	"fmt"
+
+	"github.com/golemcloud/golem-go/binding"
+
+	"golem-go-project/lib/cfg"
+	"golem-go-project/lib/witvalue"
+)
+
+func invokeAndAwait(workerURI cfg.URI, functionName string, params []witvalue.Value) (witvalue.Value, error) {
+	wasmRpc := binding.NewWasmRpc(workerURI)
+	defer wasmRpc.Drop()
+
+	return invokeResult(functionName, wasmRpc.InvokeAndAwait(functionName, bindingParams(params)))
+}
+
+func invoke(workerURI cfg.URI, functionName string, params []witvalue.Value) error {
+	wasmRpc := binding.NewWasmRpc(workerURI)
+	defer wasmRpc.Drop()
+
+	result := wasmRpc.Invoke(functionName, bindingParams(params))
+	if result.IsErr() {
+		return fmt.Errorf("invoke %s: %w", functionName, rpcError(result.UnwrapErr()))
+	}
+	return nil
+}
+
+func bindingParams(params []witvalue.Value) []binding.GolemRpc0_1_0_TypesWitValue {
+	witParams := make([]binding.GolemRpc0_1_0_TypesWitValue, len(params))
+	for i, param := range params {
+		witParams[i] = witvalue.ToBinding(param)
+	}
+	return witParams
+}
+
+func invokeResult(
+	functionName string,
+	result binding.Result[binding.GolemRpc0_1_0_TypesWitValue, binding.GolemRpc0_1_0_TypesRpcError],
+) (witvalue.Value, error) {
+	if result.IsErr() {
+		return witvalue.Value{}, fmt.Errorf("invoke %s: %w", functionName, rpcError(result.UnwrapErr()))
+	}
+
+	value, err := witvalue.FromBinding(result.Unwrap())
+	if err != nil {
+		return witvalue.Value{}, fmt.Errorf("invoke %s: %w", functionName, err)
+	}
+	return value, nil
+}
+
+func rpcError(err binding.GolemRpc0_1_0_TypesRpcError) *Error {
+	switch err.Kind() {
+	case binding.GolemRpc0_1_0_TypesRpcErrorKindProtocolError:
+		return &Error{Kind: ErrorKindProtocolError, Message: err.GetProtocolError()}
+	case binding.GolemRpc0_1_0_TypesRpcErrorKindDenied:
+		return &Error{Kind: ErrorKindDenied, Message: err.GetDenied()}
+	case binding.GolemRpc0_1_0_TypesRpcErrorKindNotFound:
+		return &Error{Kind: ErrorKindNotFound, Message: err.GetNotFound()}
+	default:
+		return &Error{Kind: ErrorKindRemoteInternalError, Message: err.GetRemoteInternalError()}
+	}
+}
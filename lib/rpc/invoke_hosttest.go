@@ -0,0 +1,144 @@
+//go:build hosttest
+
+package rpc
+
+import (
+	"fmt"
+	"strings"
+
+	"golem-go-project/lib/cfg"
+	"golem-go-project/lib/host"
+	"golem-go-project/lib/witvalue"
+)
+
+// Handler handles the invocations of a fake worker. The params and results are converted to and from wit-values
+// like in real invocations, so the params are passed in their dynamic representation.
+type Handler func(functionName string, params []any) ([]any, error)
+
+var (
+	fakeWorkers        = host.NewWorkers[Handler]()
+	fakeDeliveryErrors = host.NewWorkers[*Error]()
+)
+
+// RegisterFakeWorker registers the handler of the fake worker for the worker URI
+func RegisterFakeWorker(workerURI cfg.URI, handler Handler) {
+	fakeWorkers.Register(workerURI.Value, handler)
+}
+
+// FailFakeDelivery makes the fire-and-forget invocations of the worker URI fail with the delivery error, like when
+// the invocation cannot be enqueued, the handler of the worker is not called
+func FailFakeDelivery(workerURI cfg.URI, err *Error) {
+	fakeDeliveryErrors.Register(workerURI.Value, err)
+}
+
+// ResetFakeWorkers removes all the registered fake workers and delivery errors
+func ResetFakeWorkers() {
+	fakeWorkers.Reset()
+	fakeDeliveryErrors.Reset()
+}
+
+// invokeAndAwait invokes the function on the fake worker registered for the worker URI
+func invokeAndAwait(workerURI cfg.URI, functionName string, params []witvalue.Value) (witvalue.Value, error) {
+	handler, err := fakeWorker(workerURI, functionName)
+	if err != nil {
+		return witvalue.Value{}, err
+	}
+	return fakeInvoke(handler, functionName, params)
+}
+
+// invoke invokes the function on the fake worker registered for the worker URI. Like with real invocations, only
+// delivery errors are returned: the not-found error of unregistered workers and the errors set with FailFakeDelivery,
+// the results and errors of the handler are dropped.
+func invoke(workerURI cfg.URI, functionName string, params []witvalue.Value) error {
+	if deliveryErr, ok := fakeDeliveryErrors.Get(workerURI.Value); ok {
+		return fmt.Errorf("invoke %s: %w", functionName, deliveryErr)
+	}
+	handler, err := fakeWorker(workerURI, functionName)
+	if err != nil {
+		return err
+	}
+	dynamicParams, err := fakeParams(functionName, params)
+	if err != nil {
+		return err
+	}
+	_, _ = handler(functionName, dynamicParams)
+	return nil
+}
+
+// fakeWorker returns the handler of the fake worker registered for the worker URI, or a not-found error, like the
+// one returned for workers of unknown components
+func fakeWorker(workerURI cfg.URI, functionName string) (Handler, error) {
+	handler, ok := fakeWorkers.Get(workerURI.Value)
+	if !ok {
+		err := &Error{Kind: ErrorKindNotFound, Message: fmt.Sprintf("no fake worker registered for %s", workerURI.Value)}
+		return nil, fmt.Errorf("invoke %s: %w", functionName, err)
+	}
+	return handler, nil
+}
+
+func fakeInvoke(handler Handler, functionName string, params []witvalue.Value) (witvalue.Value, error) {
+	dynamicParams, err := fakeParams(functionName, params)
+	if err != nil {
+		return witvalue.Value{}, err
+	}
+
+	results, err := handler(functionName, dynamicParams)
+	if err != nil {
+		return witvalue.Value{}, err
+	}
+
+	value, err := witvalue.Marshal(witvalue.Tuple(results))
+	if err != nil {
+		return witvalue.Value{}, fmt.Errorf("invoke %s: %w", functionName, err)
+	}
+	return value, nil
+}
+
+// fakeParams converts the params to their dynamic representation passed to the handlers
+func fakeParams(functionName string, params []witvalue.Value) ([]any, error) {
+	dynamicParams := make([]any, len(params))
+	for i, param := range params {
+		err := witvalue.Unmarshal(param, &dynamicParams[i])
+		if err != nil {
+			return nil, fmt.Errorf("invoke %s: param #%d: %w", functionName, i, err)
+		}
+	}
+	return dynamicParams, nil
+}
+
+// FakeCounter is a fake worker of the counter components, which adds the param of add to its counter, and returns
+// the counter from get
+type FakeCounter struct {
+	Counter uint64
+	// Results is set for counters, whose add and get return result<_, error> and result<u64, error>, like
+	// component-two's
+	Results bool
+	// Err is returned as the error case of add and get, if Results is set
+	Err *Error
+}
+
+// Handle handles the invocations of the fake counter, it can be registered with RegisterFakeWorker
+func (c *FakeCounter) Handle(functionName string, params []any) ([]any, error) {
+	switch {
+	case strings.HasSuffix(functionName, ".{add}"):
+		if !c.Results {
+			c.Counter += params[0].(uint64)
+			return nil, nil
+		}
+		if c.Err != nil {
+			return []any{witvalue.Err[struct{}](*c.Err)}, nil
+		}
+		c.Counter += params[0].(uint64)
+		return []any{witvalue.Ok[struct{}, Error](struct{}{})}, nil
+	case strings.HasSuffix(functionName, ".{get}"):
+		if !c.Results {
+			return []any{c.Counter}, nil
+		}
+		if c.Err != nil {
+			return []any{witvalue.Err[uint64](*c.Err)}, nil
+		}
+		return []any{witvalue.Ok[uint64, Error](c.Counter)}, nil
+	default:
+		return nil, &Error{Kind: ErrorKindNotFound, Message: fmt.Sprintf("unknown function: %s", functionName)}
+	}
+}
@@ -0,0 +1,18 @@
+package rpc
+
+import (
+	"fmt"
+)
+
+// Result returns the result of an invocation by index, converted to T
+func Result[T any](results []any, index int) (T, error) {
+	var zero T
+	if index >= len(results) {
+		return zero, fmt.Errorf("missing result #%d, got %d result(s)", index, len(results))
+	}
+	result, ok := results[index].(T)
+	if !ok {
+		return zero, fmt.Errorf("unexpected type for result #%d, expected %T, got %T", index, zero, results[index])
+	}
+	return result, nil
+}
@@ -0,0 +1,184 @@
+// Package rpc invokes the exported functions of other workers through golem:rpc's wasm-rpc resource, without
+// generated stubs. The params and results are converted to and from wit-values with the witvalue package, see there
+// for the supported types. The results are returned as their dynamic representation, or unmarshaled into typed
+// targets with the ...Into functions.
+package rpc
+
+import (
+	"context"
+	"fmt"
+
+	"golem-go-project/lib/cfg"
+	"golem-go-project/lib/witvalue"
+)
+
+// InvokeAndAwait invokes the function on the worker, waits for it to complete and returns its results
+func InvokeAndAwait(ctx context.Context, workerURI cfg.URI, functionName string, params ...any) ([]any, error) {
+	value, err := invokeAndAwaitValue(ctx, workerURI, functionName, params)
+	if err != nil {
+		return nil, err
+	}
+	return dynamicResults(functionName, value)
+}
+
+// InvokeAndAwaitInto invokes the function on the worker, waits for it to complete and unmarshals its results into the
+// values pointed to by results
+func InvokeAndAwaitInto(ctx context.Context, workerURI cfg.URI, functionName string, params []any, results ...any) error {
+	value, err := invokeAndAwaitValue(ctx, workerURI, functionName, params)
+	if err != nil {
+		return err
+	}
+	return unmarshalResults(functionName, value, results)
+}
+
+// InvokeAndAwaitResult invokes a function returning result<T, error>, where error is the variant of Error, waits for it
+// to complete and unmarshals the ok payload into the value pointed to by ok, which is nil for result<_, error>. The
+// error case is returned as *Error.
+func InvokeAndAwaitResult(ctx context.Context, workerURI cfg.URI, functionName string, ok any, params ...any) error {
+	value, err := invokeAndAwaitValue(ctx, workerURI, functionName, params)
+	if err != nil {
+		return err
+	}
+	return unmarshalResult(functionName, value, ok)
+}
+
+// Invoke invokes the function on the worker in fire-and-forget mode: it only waits for the invocation to be enqueued,
+// and returns the delivery error, the results and the errors of the function are not available to the caller
+func Invoke(ctx context.Context, workerURI cfg.URI, functionName string, params ...any) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	witParams, err := marshalParams(functionName, params)
+	if err != nil {
+		return err
+	}
+	return invoke(workerURI, functionName, witParams)
+}
+
+// AsyncInvokeAndAwait starts invoking the function on the worker, without waiting for it to complete
+func AsyncInvokeAndAwait(ctx context.Context, workerURI cfg.URI, functionName string, params ...any) *Future {
+	return asyncInvoke(ctx, workerURI, functionName, false, params)
+}
+
+// AsyncInvokeAndAwaitResult starts invoking a function returning result<T, error>, see InvokeAndAwaitResult. The error
+// case is returned as the error of the future, and the ok payload as its only result.
+func AsyncInvokeAndAwaitResult(ctx context.Context, workerURI cfg.URI, functionName string, params ...any) *Future {
+	return asyncInvoke(ctx, workerURI, functionName, true, params)
+}
+
+func asyncInvoke(ctx context.Context, workerURI cfg.URI, functionName string, result bool, params []any) *Future {
+	if err := ctx.Err(); err != nil {
+		return FailedFuture(functionName, err)
+	}
+	witParams, err := marshalParams(functionName, params)
+	if err != nil {
+		return FailedFuture(functionName, err)
+	}
+	return &Future{
+		functionName: functionName,
+		pending:      asyncInvokeAndAwait(workerURI, functionName, witParams),
+		result:       result,
+	}
+}
+
+// Client invokes any function of a worker by name, with dynamic params and results
+type Client struct {
+	workerURI cfg.URI
+}
+
+// NewClient returns a client for the worker with the URI
+func NewClient(workerURI cfg.URI) *Client {
+	return &Client{workerURI: workerURI}
+}
+
+// InvokeAndAwait invokes the function on the worker, see InvokeAndAwait
+func (c *Client) InvokeAndAwait(ctx context.Context, functionName string, params ...any) ([]any, error) {
+	return InvokeAndAwait(ctx, c.workerURI, functionName, params...)
+}
+
+// InvokeAndAwaitInto invokes the function on the worker, see InvokeAndAwaitInto
+func (c *Client) InvokeAndAwaitInto(ctx context.Context, functionName string, params []any, results ...any) error {
+	return InvokeAndAwaitInto(ctx, c.workerURI, functionName, params, results...)
+}
+
+// InvokeAndAwaitResult invokes a function returning result<T, error> on the worker, see InvokeAndAwaitResult
+func (c *Client) InvokeAndAwaitResult(ctx context.Context, functionName string, ok any, params ...any) error {
+	return InvokeAndAwaitResult(ctx, c.workerURI, functionName, ok, params...)
+}
+
+// Invoke invokes the function on the worker in fire-and-forget mode, see Invoke
+func (c *Client) Invoke(ctx context.Context, functionName string, params ...any) error {
+	return Invoke(ctx, c.workerURI, functionName, params...)
+}
+
+// AsyncInvokeAndAwait starts invoking the function on the worker, see AsyncInvokeAndAwait
+func (c *Client) AsyncInvokeAndAwait(ctx context.Context, functionName string, params ...any) *Future {
+	return AsyncInvokeAndAwait(ctx, c.workerURI, functionName, params...)
+}
+
+// AsyncInvokeAndAwaitResult starts invoking a function returning result<T, error> on the worker, see
+// AsyncInvokeAndAwaitResult
+func (c *Client) AsyncInvokeAndAwaitResult(ctx context.Context, functionName string, params ...any) *Future {
+	return AsyncInvokeAndAwaitResult(ctx, c.workerURI, functionName, params...)
+}
+
+func invokeAndAwaitValue(ctx context.Context, workerURI cfg.URI, functionName string, params []any) (witvalue.Value, error) {
+	if err := ctx.Err(); err != nil {
+		return witvalue.Value{}, err
+	}
+	witParams, err := marshalParams(functionName, params)
+	if err != nil {
+		return witvalue.Value{}, err
+	}
+	return invokeAndAwait(workerURI, functionName, witParams)
+}
+
+func marshalParams(functionName string, params []any) ([]witvalue.Value, error) {
+	witParams := make([]witvalue.Value, len(params))
+	for i, param := range params {
+		witParam, err := witvalue.Marshal(param)
+		if err != nil {
+			return nil, fmt.Errorf("invoke %s: param #%d: %w", functionName, i, err)
+		}
+		witParams[i] = witParam
+	}
+	return witParams, nil
+}
+
+// dynamicResults converts the results of an invocation, which are returned as a tuple of the result values
+func dynamicResults(functionName string, value witvalue.Value) ([]any, error) {
+	var results witvalue.Tuple
+	err := witvalue.Unmarshal(value, &results)
+	if err != nil {
+		return nil, fmt.Errorf("invoke %s: results: %w", functionName, err)
+	}
+	return results, nil
+}
+
+func unmarshalResults(functionName string, value witvalue.Value, results []any) error {
+	err := witvalue.UnmarshalTuple(value, results...)
+	if err != nil {
+		return fmt.Errorf("invoke %s: results: %w", functionName, err)
+	}
+	return nil
+}
+
+// unmarshalResult unmarshals the result<T, error> returned by the function, see InvokeAndAwaitResult
+func unmarshalResult(functionName string, value witvalue.Value, ok any) error {
+	var result witvalue.Result[witvalue.Payload, Error]
+	err := witvalue.UnmarshalTuple(value, &result)
+	if err != nil {
+		return fmt.Errorf("invoke %s: results: %w", functionName, err)
+	}
+	if result.IsErr {
+		return fmt.Errorf("invoke %s: %w", functionName, &result.Err)
+	}
+	if ok == nil || result.Ok.IsNone() {
+		return nil
+	}
+	err = result.Ok.Unmarshal(ok)
+	if err != nil {
+		return fmt.Errorf("invoke %s: results: %w", functionName, err)
+	}
+	return nil
+}
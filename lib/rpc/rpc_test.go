@@ -0,0 +1,105 @@
+//go:build hosttest
+
+package rpc
+
+import (
+	"context"
+	"testing"
+
+	"golem-go-project/lib/cfg"
+	"golem-go-project/lib/witvalue"
+)
+
+type item struct {
+	Name  string
+	Count uint64
+}
+
+func TestClientInvokesAnyFunctionWithDynamicValues(t *testing.T) {
+	workerURI := cfg.URI{Value: "urn:worker:test/worker"}
+	RegisterFakeWorker(workerURI, func(functionName string, params []any) ([]any, error) {
+		if functionName != "golem:test/api.{lookup}" {
+			return nil, &Error{Kind: ErrorKindNotFound, Message: functionName}
+		}
+		// records are passed in their dynamic representation
+		query := params[0].(witvalue.Record)
+		return []any{[]item{{Name: query[0].(string), Count: query[1].(uint64) + 1}}, witvalue.Ok[uint64, string](2)}, nil
+	})
+	t.Cleanup(ResetFakeWorkers)
+
+	ctx := context.Background()
+	client := NewClient(workerURI)
+
+	var items []item
+	var result witvalue.Result[uint64, string]
+	err := client.InvokeAndAwaitInto(ctx, "golem:test/api.{lookup}", []any{item{Name: "a", Count: 1}}, &items, &result)
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	if len(items) != 1 || items[0] != (item{Name: "a", Count: 2}) || result.IsErr || result.Ok != 2 {
+		t.Fatalf("Unexpected results: %#v, %#v", items, result)
+	}
+
+	results, err := client.AsyncInvokeAndAwait(ctx, "golem:test/api.{lookup}", item{Name: "b"}).Await(ctx)
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	expected := []any{[]any{witvalue.Record{"b", uint64(1)}}, witvalue.Ok[any, any](uint64(2))}
+	if len(results) != 2 || results[0].([]any)[0].(witvalue.Record)[0] != "b" || results[1] != expected[1] {
+		t.Fatalf("Expected results: %#v, actual: %#v", expected, results)
+	}
+
+	_, err = client.InvokeAndAwait(ctx, "golem:test/api.{missing}")
+	if rpcErr, ok := err.(*Error); !ok || rpcErr.Kind != ErrorKindNotFound {
+		t.Fatalf("Expected not-found error, actual: %+v", err)
+	}
+}
+
+func TestClientReturnsNotFoundForUnregisteredWorker(t *testing.T) {
+	ctx := context.Background()
+	client := NewClient(cfg.URI{Value: "urn:worker:test/unregistered"})
+
+	_, err := client.InvokeAndAwait(ctx, "golem:test/api.{lookup}")
+	if rpcErr := AsError(err); rpcErr.Kind != ErrorKindNotFound {
+		t.Fatalf("Expected not-found error, actual: %+v", err)
+	}
+
+	_, err = client.AsyncInvokeAndAwait(ctx, "golem:test/api.{lookup}").Await(ctx)
+	if rpcErr := AsError(err); rpcErr.Kind != ErrorKindNotFound {
+		t.Fatalf("Expected not-found error, actual: %+v", err)
+	}
+
+	err = client.Invoke(ctx, "golem:test/api.{lookup}")
+	if rpcErr := AsError(err); rpcErr.Kind != ErrorKindNotFound {
+		t.Fatalf("Expected not-found delivery error, actual: %+v", err)
+	}
+}
+
+func TestInvokeReturnsOnlyDeliveryErrors(t *testing.T) {
+	workerURI := cfg.URI{Value: "urn:worker:test/worker"}
+	called := false
+	RegisterFakeWorker(workerURI, func(functionName string, params []any) ([]any, error) {
+		called = true
+		return nil, &Error{Kind: ErrorKindRemoteInternalError, Message: "failed"}
+	})
+	t.Cleanup(ResetFakeWorkers)
+
+	ctx := context.Background()
+	err := Invoke(ctx, workerURI, "golem:test/api.{add}", uint64(1))
+	if err != nil {
+		t.Fatalf("Expected the error of the handler to be dropped, actual: %+v", err)
+	}
+	if !called {
+		t.Fatalf("Expected the handler to be called")
+	}
+
+	called = false
+	FailFakeDelivery(workerURI, &Error{Kind: ErrorKindDenied, Message: "denied"})
+	err = Invoke(ctx, workerURI, "golem:test/api.{add}", uint64(1))
+	if rpcErr := AsError(err); rpcErr.Kind != ErrorKindDenied {
+		t.Fatalf("Expected denied delivery error, actual: %+v", err)
+	}
+	if called {
+		t.Fatalf("Expected the handler not to be called")
+	}
+}
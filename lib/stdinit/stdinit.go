@@ -0,0 +1,12 @@
+//go:build !hosttest
+
+package stdinit
+
+import (
+	"github.com/golemcloud/golem-go/std"
+)
+
+// Init initializes the standard lib's os and net/http packages with the WASI environment and wrappers
+func Init() {
+	std.Init(std.Packages{Os: true, NetHttp: true})
+}
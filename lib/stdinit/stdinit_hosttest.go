@@ -0,0 +1,6 @@
+//go:build hosttest
+
+package stdinit
+
+// Init is a no-op on the host, where the standard lib's os and net/http packages work without the WASI wrappers
+func Init() {}
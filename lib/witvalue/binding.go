@@ -0,0 +1,166 @@
+//go:build !hosttest
+
+package witvalue
+
+import (
+	"fmt"
+
+	"github.com/golemcloud/golem-go/binding"
+)
+
+// ToBinding converts the value to the wit-value of the golem-go binding
+func ToBinding(value Value) binding.GolemRpc0_1_0_TypesWitValue {
+	nodes := make([]binding.GolemRpc0_1_0_TypesWitNode, len(value.Nodes))
+	for i, node := range value.Nodes {
+		nodes[i] = toBindingNode(node)
+	}
+	return binding.GolemRpc0_1_0_TypesWitValue{Nodes: nodes}
+}
+
+func toBindingNode(node Node) binding.GolemRpc0_1_0_TypesWitNode {
+	switch node.Kind {
+	case NodeKindRecord:
+		return binding.GolemRpc0_1_0_TypesWitNodeRecordValue(node.Children)
+	case NodeKindVariant:
+		return binding.GolemRpc0_1_0_TypesWitNodeVariantValue(binding.GolemRpc0_1_0_TypesTuple2U32OptionNodeIndexTT{
+			F0: node.Case,
+			F1: toBindingChild(node.Child),
+		})
+	case NodeKindEnum:
+		return binding.GolemRpc0_1_0_TypesWitNodeEnumValue(node.Case)
+	case NodeKindFlags:
+		return binding.GolemRpc0_1_0_TypesWitNodeFlagsValue(node.Flags)
+	case NodeKindTuple:
+		return binding.GolemRpc0_1_0_TypesWitNodeTupleValue(node.Children)
+	case NodeKindList:
+		return binding.GolemRpc0_1_0_TypesWitNodeListValue(node.Children)
+	case NodeKindOption:
+		return binding.GolemRpc0_1_0_TypesWitNodeOptionValue(toBindingChild(node.Child))
+	case NodeKindResult:
+		if node.IsErr {
+			return binding.GolemRpc0_1_0_TypesWitNodeResultValue(binding.Err[
+				binding.Option[binding.GolemRpc0_1_0_TypesNodeIndex],
+				binding.Option[binding.GolemRpc0_1_0_TypesNodeIndex],
+			](toBindingChild(node.Child)))
+		}
+		return binding.GolemRpc0_1_0_TypesWitNodeResultValue(binding.Ok[
+			binding.Option[binding.GolemRpc0_1_0_TypesNodeIndex],
+			binding.Option[binding.GolemRpc0_1_0_TypesNodeIndex],
+		](toBindingChild(node.Child)))
+	case NodeKindPrimU8:
+		return binding.GolemRpc0_1_0_TypesWitNodePrimU8(node.Prim.(uint8))
+	case NodeKindPrimU16:
+		return binding.GolemRpc0_1_0_TypesWitNodePrimU16(node.Prim.(uint16))
+	case NodeKindPrimU32:
+		return binding.GolemRpc0_1_0_TypesWitNodePrimU32(node.Prim.(uint32))
+	case NodeKindPrimU64:
+		return binding.GolemRpc0_1_0_TypesWitNodePrimU64(node.Prim.(uint64))
+	case NodeKindPrimS8:
+		return binding.GolemRpc0_1_0_TypesWitNodePrimS8(node.Prim.(int8))
+	case NodeKindPrimS16:
+		return binding.GolemRpc0_1_0_TypesWitNodePrimS16(node.Prim.(int16))
+	case NodeKindPrimS32:
+		return binding.GolemRpc0_1_0_TypesWitNodePrimS32(node.Prim.(int32))
+	case NodeKindPrimS64:
+		return binding.GolemRpc0_1_0_TypesWitNodePrimS64(node.Prim.(int64))
+	case NodeKindPrimFloat32:
+		return binding.GolemRpc0_1_0_TypesWitNodePrimFloat32(node.Prim.(float32))
+	case NodeKindPrimFloat64:
+		return binding.GolemRpc0_1_0_TypesWitNodePrimFloat64(node.Prim.(float64))
+	case NodeKindPrimChar:
+		return binding.GolemRpc0_1_0_TypesWitNodePrimChar(rune(node.Prim.(Char)))
+	case NodeKindPrimBool:
+		return binding.GolemRpc0_1_0_TypesWitNodePrimBool(node.Prim.(bool))
+	case NodeKindPrimString:
+		return binding.GolemRpc0_1_0_TypesWitNodePrimString(node.Prim.(string))
+	default:
+		return binding.GolemRpc0_1_0_TypesWitNodeHandle(binding.GolemRpc0_1_0_TypesTuple2UriU64T{
+			F0: binding.GolemRpc0_1_0_TypesUri{Value: node.Handle.URI},
+			F1: node.Handle.ResourceID,
+		})
+	}
+}
+
+func toBindingChild(child int32) binding.Option[binding.GolemRpc0_1_0_TypesNodeIndex] {
+	if child == NoChild {
+		return binding.None[binding.GolemRpc0_1_0_TypesNodeIndex]()
+	}
+	return binding.Some(child)
+}
+
+// FromBinding converts the wit-value of the golem-go binding to a value
+func FromBinding(value binding.GolemRpc0_1_0_TypesWitValue) (Value, error) {
+	nodes := make([]Node, len(value.Nodes))
+	for i, bindingNode := range value.Nodes {
+		node, err := fromBindingNode(bindingNode)
+		if err != nil {
+			return Value{}, fmt.Errorf("witvalue: node #%d: %w", i, err)
+		}
+		nodes[i] = node
+	}
+	return Value{Nodes: nodes}, nil
+}
+
+func fromBindingNode(node binding.GolemRpc0_1_0_TypesWitNode) (Node, error) {
+	switch node.Kind() {
+	case binding.GolemRpc0_1_0_TypesWitNodeKindRecordValue:
+		return Node{Kind: NodeKindRecord, Children: node.GetRecordValue(), Child: NoChild}, nil
+	case binding.GolemRpc0_1_0_TypesWitNodeKindVariantValue:
+		variant := node.GetVariantValue()
+		return Node{Kind: NodeKindVariant, Case: variant.F0, Child: fromBindingChild(variant.F1)}, nil
+	case binding.GolemRpc0_1_0_TypesWitNodeKindEnumValue:
+		return Node{Kind: NodeKindEnum, Case: node.GetEnumValue(), Child: NoChild}, nil
+	case binding.GolemRpc0_1_0_TypesWitNodeKindFlagsValue:
+		return Node{Kind: NodeKindFlags, Flags: node.GetFlagsValue(), Child: NoChild}, nil
+	case binding.GolemRpc0_1_0_TypesWitNodeKindTupleValue:
+		return Node{Kind: NodeKindTuple, Children: node.GetTupleValue(), Child: NoChild}, nil
+	case binding.GolemRpc0_1_0_TypesWitNodeKindListValue:
+		return Node{Kind: NodeKindList, Children: node.GetListValue(), Child: NoChild}, nil
+	case binding.GolemRpc0_1_0_TypesWitNodeKindOptionValue:
+		return Node{Kind: NodeKindOption, Child: fromBindingChild(node.GetOptionValue())}, nil
+	case binding.GolemRpc0_1_0_TypesWitNodeKindResultValue:
+		result := node.GetResultValue()
+		if result.IsErr() {
+			return Node{Kind: NodeKindResult, IsErr: true, Child: fromBindingChild(result.UnwrapErr())}, nil
+		}
+		return Node{Kind: NodeKindResult, Child: fromBindingChild(result.Unwrap())}, nil
+	case binding.GolemRpc0_1_0_TypesWitNodeKindPrimU8:
+		return primNode(NodeKindPrimU8, node.GetPrimU8()), nil
+	case binding.GolemRpc0_1_0_TypesWitNodeKindPrimU16:
+		return primNode(NodeKindPrimU16, node.GetPrimU16()), nil
+	case binding.GolemRpc0_1_0_TypesWitNodeKindPrimU32:
+		return primNode(NodeKindPrimU32, node.GetPrimU32()), nil
+	case binding.GolemRpc0_1_0_TypesWitNodeKindPrimU64:
+		return primNode(NodeKindPrimU64, node.GetPrimU64()), nil
+	case binding.GolemRpc0_1_0_TypesWitNodeKindPrimS8:
+		return primNode(NodeKindPrimS8, node.GetPrimS8()), nil
+	case binding.GolemRpc0_1_0_TypesWitNodeKindPrimS16:
+		return primNode(NodeKindPrimS16, node.GetPrimS16()), nil
+	case binding.GolemRpc0_1_0_TypesWitNodeKindPrimS32:
+		return primNode(NodeKindPrimS32, node.GetPrimS32()), nil
+	case binding.GolemRpc0_1_0_TypesWitNodeKindPrimS64:
+		return primNode(NodeKindPrimS64, node.GetPrimS64()), nil
+	case binding.GolemRpc0_1_0_TypesWitNodeKindPrimFloat32:
+		return primNode(NodeKindPrimFloat32, node.GetPrimFloat32()), nil
+	case binding.GolemRpc0_1_0_TypesWitNodeKindPrimFloat64:
+		return primNode(NodeKindPrimFloat64, node.GetPrimFloat64()), nil
+	case binding.GolemRpc0_1_0_TypesWitNodeKindPrimChar:
+		return primNode(NodeKindPrimChar, Char(node.GetPrimChar())), nil
+	case binding.GolemRpc0_1_0_TypesWitNodeKindPrimBool:
+		return primNode(NodeKindPrimBool, node.GetPrimBool()), nil
+	case binding.GolemRpc0_1_0_TypesWitNodeKindPrimString:
+		return primNode(NodeKindPrimString, node.GetPrimString()), nil
+	case binding.GolemRpc0_1_0_TypesWitNodeKindHandle:
+		handle := node.GetHandle()
+		return Node{Kind: NodeKindHandle, Handle: Handle{URI: handle.F0.Value, ResourceID: handle.F1}, Child: NoChild}, nil
+	default:
+		return Node{}, fmt.Errorf("unsupported wit node kind: %d", node.Kind())
+	}
+}
+
+func fromBindingChild(child binding.Option[binding.GolemRpc0_1_0_TypesNodeIndex]) int32 {
+	if child.IsNone() {
+		return NoChild
+	}
+	return child.Unwrap()
+}
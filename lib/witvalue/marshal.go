@@ -0,0 +1,186 @@
+package witvalue
+
+import (
+	"fmt"
+	"reflect"
+)
+
+var (
+	charType    = reflect.TypeOf(Char(0))
+	enumType    = reflect.TypeOf(Enum(0))
+	flagsType   = reflect.TypeOf(Flags(nil))
+	handleType  = reflect.TypeOf(Handle{})
+	recordType  = reflect.TypeOf(Record(nil))
+	tupleType   = reflect.TypeOf(Tuple(nil))
+	variantType = reflect.TypeOf(Variant{})
+	unitType    = reflect.TypeOf(struct{}{})
+)
+
+// Marshal converts the go value to a wit-value tree
+func Marshal(value any) (Value, error) {
+	m := &marshaler{}
+	_, err := m.marshal(reflect.ValueOf(value))
+	if err != nil {
+		return Value{}, fmt.Errorf("witvalue: marshal %T: %w", value, err)
+	}
+	return Value{Nodes: m.nodes}, nil
+}
+
+type marshaler struct {
+	nodes []Node
+}
+
+// marshal appends the node of the value, and then the nodes of its children, and returns the index of the node
+func (m *marshaler) marshal(rv reflect.Value) (int32, error) {
+	if !rv.IsValid() {
+		return 0, fmt.Errorf("unsupported nil value")
+	}
+
+	index := int32(len(m.nodes))
+	m.nodes = append(m.nodes, Node{Child: NoChild})
+	node, err := m.marshalNode(rv)
+	if err != nil {
+		return 0, err
+	}
+	m.nodes[index] = node
+	return index, nil
+}
+
+func (m *marshaler) marshalNode(rv reflect.Value) (Node, error) {
+	for rv.Kind() == reflect.Interface {
+		if rv.IsNil() {
+			return Node{}, fmt.Errorf("unsupported nil value")
+		}
+		rv = rv.Elem()
+	}
+
+	// pointers are options, even if they implement the interfaces through the methods of their element type
+	if rv.Kind() != reflect.Pointer && rv.CanInterface() {
+		switch v := rv.Interface().(type) {
+		case VariantMarshaler:
+			variant, err := v.MarshalWitVariant()
+			if err != nil {
+				return Node{}, err
+			}
+			return m.marshalVariant(variant)
+		case resultMarshaler:
+			isErr, payload := v.marshalWitResult()
+			child, err := m.marshalPayload(reflect.ValueOf(payload))
+			if err != nil {
+				return Node{}, err
+			}
+			return Node{Kind: NodeKindResult, IsErr: isErr, Child: child}, nil
+		}
+	}
+
+	switch rv.Type() {
+	case charType:
+		return primNode(NodeKindPrimChar, Char(rv.Int())), nil
+	case enumType:
+		return Node{Kind: NodeKindEnum, Case: uint32(rv.Uint()), Child: NoChild}, nil
+	case flagsType:
+		return Node{Kind: NodeKindFlags, Flags: append([]bool(nil), rv.Interface().(Flags)...), Child: NoChild}, nil
+	case handleType:
+		return Node{Kind: NodeKindHandle, Handle: rv.Interface().(Handle), Child: NoChild}, nil
+	case recordType:
+		return m.marshalItems(NodeKindRecord, rv)
+	case tupleType:
+		return m.marshalItems(NodeKindTuple, rv)
+	case variantType:
+		return m.marshalVariant(rv.Interface().(Variant))
+	}
+
+	switch rv.Kind() {
+	case reflect.Bool:
+		return primNode(NodeKindPrimBool, rv.Bool()), nil
+	case reflect.Uint8:
+		return primNode(NodeKindPrimU8, uint8(rv.Uint())), nil
+	case reflect.Uint16:
+		return primNode(NodeKindPrimU16, uint16(rv.Uint())), nil
+	case reflect.Uint32:
+		return primNode(NodeKindPrimU32, uint32(rv.Uint())), nil
+	case reflect.Uint64, reflect.Uint:
+		return primNode(NodeKindPrimU64, rv.Uint()), nil
+	case reflect.Int8:
+		return primNode(NodeKindPrimS8, int8(rv.Int())), nil
+	case reflect.Int16:
+		return primNode(NodeKindPrimS16, int16(rv.Int())), nil
+	case reflect.Int32:
+		return primNode(NodeKindPrimS32, int32(rv.Int())), nil
+	case reflect.Int64, reflect.Int:
+		return primNode(NodeKindPrimS64, rv.Int()), nil
+	case reflect.Float32:
+		return primNode(NodeKindPrimFloat32, float32(rv.Float())), nil
+	case reflect.Float64:
+		return primNode(NodeKindPrimFloat64, rv.Float()), nil
+	case reflect.String:
+		return primNode(NodeKindPrimString, rv.String()), nil
+	case reflect.Slice, reflect.Array:
+		return m.marshalItems(NodeKindList, rv)
+	case reflect.Pointer:
+		if rv.IsNil() {
+			return Node{Kind: NodeKindOption, Child: NoChild}, nil
+		}
+		child, err := m.marshal(rv.Elem())
+		if err != nil {
+			return Node{}, err
+		}
+		return Node{Kind: NodeKindOption, Child: child}, nil
+	case reflect.Struct:
+		var children []int32
+		for _, field := range recordFields(rv.Type()) {
+			child, err := m.marshal(rv.Field(field))
+			if err != nil {
+				return Node{}, fmt.Errorf("field %s: %w", rv.Type().Field(field).Name, err)
+			}
+			children = append(children, child)
+		}
+		return Node{Kind: NodeKindRecord, Children: children, Child: NoChild}, nil
+	default:
+		return Node{}, fmt.Errorf("unsupported type: %s", rv.Type())
+	}
+}
+
+func (m *marshaler) marshalItems(kind NodeKind, rv reflect.Value) (Node, error) {
+	children := make([]int32, rv.Len())
+	for i := range children {
+		child, err := m.marshal(rv.Index(i))
+		if err != nil {
+			return Node{}, fmt.Errorf("item #%d: %w", i, err)
+		}
+		children[i] = child
+	}
+	return Node{Kind: kind, Children: children, Child: NoChild}, nil
+}
+
+func (m *marshaler) marshalVariant(variant Variant) (Node, error) {
+	child, err := m.marshalPayload(reflect.ValueOf(variant.Payload))
+	if err != nil {
+		return Node{}, fmt.Errorf("case %d: %w", variant.Case, err)
+	}
+	return Node{Kind: NodeKindVariant, Case: variant.Case, Child: child}, nil
+}
+
+// marshalPayload marshals the payload of variants and results, nil interfaces and struct{} are mapped to NoChild
+func (m *marshaler) marshalPayload(rv reflect.Value) (int32, error) {
+	if !rv.IsValid() || rv.Type() == unitType {
+		return NoChild, nil
+	}
+	return m.marshal(rv)
+}
+
+func primNode(kind NodeKind, value any) Node {
+	return Node{Kind: kind, Prim: value, Child: NoChild}
+}
+
+// recordFields returns the indexes of the fields mapped to record fields
+func recordFields(t reflect.Type) []int {
+	var fields []int
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.IsExported() && field.Tag.Get("wit") != "-" {
+			fields = append(fields, i)
+		}
+	}
+	return fields
+}
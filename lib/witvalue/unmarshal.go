@@ -0,0 +1,285 @@
+package witvalue
+
+import (
+	"fmt"
+	"reflect"
+)
+
+var (
+	variantUnmarshalerType = reflect.TypeOf((*VariantUnmarshaler)(nil)).Elem()
+	resultUnmarshalerType  = reflect.TypeOf((*resultUnmarshaler)(nil)).Elem()
+)
+
+// Unmarshal converts the wit-value tree to the go value pointed to by target
+func Unmarshal(value Value, target any) error {
+	err := unmarshalNode(value, 0, target)
+	if err != nil {
+		return fmt.Errorf("witvalue: unmarshal to %T: %w", target, err)
+	}
+	return nil
+}
+
+// UnmarshalTuple converts the items of a tuple, e.g. the results of an invocation, to the go values pointed to by
+// the targets, the number of targets must match the number of items
+func UnmarshalTuple(value Value, targets ...any) error {
+	root, err := value.node(0)
+	if err != nil {
+		return fmt.Errorf("witvalue: unmarshal tuple: %w", err)
+	}
+	if root.Kind != NodeKindTuple {
+		return fmt.Errorf("witvalue: unmarshal tuple: expected tuple, got %s", root.Kind)
+	}
+	if len(root.Children) != len(targets) {
+		return fmt.Errorf("witvalue: unmarshal tuple: expected %d item(s), got %d", len(targets), len(root.Children))
+	}
+	for i, child := range root.Children {
+		err := unmarshalNode(value, child, targets[i])
+		if err != nil {
+			return fmt.Errorf("witvalue: unmarshal tuple: item #%d to %T: %w", i, targets[i], err)
+		}
+	}
+	return nil
+}
+
+func unmarshalNode(value Value, index int32, target any) error {
+	rv := reflect.ValueOf(target)
+	if rv.Kind() != reflect.Pointer || rv.IsNil() {
+		return fmt.Errorf("target must be a non-nil pointer")
+	}
+	u := &unmarshaler{value: value}
+	return u.unmarshal(index, rv.Elem())
+}
+
+func unmarshalPayload(payload Payload, target any) error {
+	if p, ok := target.(*Payload); ok {
+		*p = payload
+		return nil
+	}
+
+	rv := reflect.ValueOf(target).Elem()
+	if payload.IsNone() {
+		if rv.Kind() == reflect.Interface || rv.Type() == unitType {
+			rv.Set(reflect.Zero(rv.Type()))
+			return nil
+		}
+		return fmt.Errorf("missing payload for %s", rv.Type())
+	}
+	return payload.Unmarshal(target)
+}
+
+type unmarshaler struct {
+	value Value
+}
+
+func (u *unmarshaler) unmarshal(index int32, rv reflect.Value) error {
+	node, err := u.value.node(index)
+	if err != nil {
+		return err
+	}
+
+	if rv.Kind() != reflect.Pointer && rv.Kind() != reflect.Interface {
+		switch ptrType := reflect.PointerTo(rv.Type()); {
+		case ptrType.Implements(variantUnmarshalerType):
+			if err := expectKind(node, NodeKindVariant); err != nil {
+				return err
+			}
+			payload := Payload{value: u.value, index: node.Child}
+			return rv.Addr().Interface().(VariantUnmarshaler).UnmarshalWitVariant(node.Case, payload)
+		case ptrType.Implements(resultUnmarshalerType):
+			if err := expectKind(node, NodeKindResult); err != nil {
+				return err
+			}
+			payload := Payload{value: u.value, index: node.Child}
+			return rv.Addr().Interface().(resultUnmarshaler).unmarshalWitResult(node.IsErr, payload)
+		}
+	}
+
+	switch rv.Type() {
+	case charType, enumType, flagsType, handleType, recordType, tupleType, variantType:
+		dynamic, err := u.dynamic(node)
+		if err != nil {
+			return err
+		}
+		if reflect.TypeOf(dynamic) != rv.Type() {
+			return fmt.Errorf("expected %s, got %s", rv.Type(), node.Kind)
+		}
+		rv.Set(reflect.ValueOf(dynamic))
+		return nil
+	}
+
+	switch rv.Kind() {
+	case reflect.Bool:
+		return u.setPrim(node, NodeKindPrimBool, rv)
+	case reflect.Uint8:
+		return u.setPrim(node, NodeKindPrimU8, rv)
+	case reflect.Uint16:
+		return u.setPrim(node, NodeKindPrimU16, rv)
+	case reflect.Uint32:
+		return u.setPrim(node, NodeKindPrimU32, rv)
+	case reflect.Uint64, reflect.Uint:
+		return u.setPrim(node, NodeKindPrimU64, rv)
+	case reflect.Int8:
+		return u.setPrim(node, NodeKindPrimS8, rv)
+	case reflect.Int16:
+		return u.setPrim(node, NodeKindPrimS16, rv)
+	case reflect.Int32:
+		return u.setPrim(node, NodeKindPrimS32, rv)
+	case reflect.Int64, reflect.Int:
+		return u.setPrim(node, NodeKindPrimS64, rv)
+	case reflect.Float32:
+		return u.setPrim(node, NodeKindPrimFloat32, rv)
+	case reflect.Float64:
+		return u.setPrim(node, NodeKindPrimFloat64, rv)
+	case reflect.String:
+		return u.setPrim(node, NodeKindPrimString, rv)
+	case reflect.Slice:
+		if err := expectKind(node, NodeKindList); err != nil {
+			return err
+		}
+		items := reflect.MakeSlice(rv.Type(), len(node.Children), len(node.Children))
+		for i, child := range node.Children {
+			if err := u.unmarshal(child, items.Index(i)); err != nil {
+				return fmt.Errorf("item #%d: %w", i, err)
+			}
+		}
+		rv.Set(items)
+		return nil
+	case reflect.Array:
+		if err := expectKind(node, NodeKindList); err != nil {
+			return err
+		}
+		if len(node.Children) != rv.Len() {
+			return fmt.Errorf("expected %d item(s), got %d", rv.Len(), len(node.Children))
+		}
+		for i, child := range node.Children {
+			if err := u.unmarshal(child, rv.Index(i)); err != nil {
+				return fmt.Errorf("item #%d: %w", i, err)
+			}
+		}
+		return nil
+	case reflect.Pointer:
+		if err := expectKind(node, NodeKindOption); err != nil {
+			return err
+		}
+		if node.Child == NoChild {
+			rv.Set(reflect.Zero(rv.Type()))
+			return nil
+		}
+		elem := reflect.New(rv.Type().Elem())
+		if err := u.unmarshal(node.Child, elem.Elem()); err != nil {
+			return err
+		}
+		rv.Set(elem)
+		return nil
+	case reflect.Struct:
+		if node.Kind != NodeKindRecord && node.Kind != NodeKindTuple {
+			return fmt.Errorf("expected record, got %s", node.Kind)
+		}
+		fields := recordFields(rv.Type())
+		if len(fields) != len(node.Children) {
+			return fmt.Errorf("expected %d field(s), got %d", len(fields), len(node.Children))
+		}
+		for i, field := range fields {
+			if err := u.unmarshal(node.Children[i], rv.Field(field)); err != nil {
+				return fmt.Errorf("field %s: %w", rv.Type().Field(field).Name, err)
+			}
+		}
+		return nil
+	case reflect.Interface:
+		if rv.Type().NumMethod() != 0 {
+			return fmt.Errorf("unsupported type: %s", rv.Type())
+		}
+		dynamic, err := u.dynamic(node)
+		if err != nil {
+			return err
+		}
+		if dynamic == nil {
+			rv.Set(reflect.Zero(rv.Type()))
+		} else {
+			rv.Set(reflect.ValueOf(dynamic))
+		}
+		return nil
+	default:
+		return fmt.Errorf("unsupported type: %s", rv.Type())
+	}
+}
+
+func (u *unmarshaler) setPrim(node Node, kind NodeKind, rv reflect.Value) error {
+	if err := expectKind(node, kind); err != nil {
+		return err
+	}
+	rv.Set(reflect.ValueOf(node.Prim).Convert(rv.Type()))
+	return nil
+}
+
+// dynamic returns the dynamic representation of the node
+func (u *unmarshaler) dynamic(node Node) (any, error) {
+	switch node.Kind {
+	case NodeKindRecord:
+		items, err := u.dynamicItems(node)
+		return Record(items), err
+	case NodeKindTuple:
+		items, err := u.dynamicItems(node)
+		return Tuple(items), err
+	case NodeKindList:
+		return u.dynamicItems(node)
+	case NodeKindVariant:
+		payload, err := u.dynamicChild(node.Child)
+		if err != nil {
+			return nil, fmt.Errorf("case %d: %w", node.Case, err)
+		}
+		return Variant{Case: node.Case, Payload: payload}, nil
+	case NodeKindEnum:
+		return Enum(node.Case), nil
+	case NodeKindFlags:
+		return Flags(append([]bool(nil), node.Flags...)), nil
+	case NodeKindOption:
+		return u.dynamicChild(node.Child)
+	case NodeKindResult:
+		payload, err := u.dynamicChild(node.Child)
+		if err != nil {
+			return nil, err
+		}
+		if node.IsErr {
+			return Err[any, any](payload), nil
+		}
+		return Ok[any, any](payload), nil
+	case NodeKindHandle:
+		return node.Handle, nil
+	default:
+		if _, ok := nodeKindNames[node.Kind]; !ok || node.Prim == nil {
+			return nil, fmt.Errorf("unsupported node: %s", node.Kind)
+		}
+		return node.Prim, nil
+	}
+}
+
+func (u *unmarshaler) dynamicChild(index int32) (any, error) {
+	if index == NoChild {
+		return nil, nil
+	}
+	node, err := u.value.node(index)
+	if err != nil {
+		return nil, err
+	}
+	return u.dynamic(node)
+}
+
+func (u *unmarshaler) dynamicItems(node Node) ([]any, error) {
+	items := make([]any, len(node.Children))
+	for i, child := range node.Children {
+		item, err := u.dynamicChild(child)
+		if err != nil {
+			return nil, fmt.Errorf("item #%d: %w", i, err)
+		}
+		items[i] = item
+	}
+	return items, nil
+}
+
+func expectKind(node Node, kind NodeKind) error {
+	if node.Kind != kind {
+		return fmt.Errorf("expected %s, got %s", kind, node.Kind)
+	}
+	return nil
+}
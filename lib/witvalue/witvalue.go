@@ -0,0 +1,215 @@
+// Package witvalue converts go values to and from the wit-value node trees of golem:rpc, which are used by the
+// wasm-rpc resource for the params and results of dynamic invocations.
+//
+// The go types are mapped to WIT types as follows:
+//   - bool, the sized integer types, float32, float64 and string to the primitive types, int and uint to s64 and u64
+//   - Char to char (rune cannot be told apart from int32, which is mapped to s32)
+//   - structs to records, with the exported fields in order, fields tagged with `wit:"-"` are skipped
+//   - slices and arrays to lists, pointers to options, nil is none
+//   - Result to results, Variant and the types implementing VariantMarshaler to variants
+//   - Enum, Flags and Handle to enums, flags and resource handles
+//   - Record and Tuple to records and tuples of the dynamic values
+//
+// The same types can be unmarshaled, and unmarshaling to an interface{} value returns the dynamic representation:
+// records as Record, tuples as Tuple, lists as []any, options as nil or the value, results as Result[any, any],
+// variants as Variant, and the primitive types as above.
+//
+// The node trees are represented by Value, which can be converted to and from the golem-go binding types with
+// ToBinding and FromBinding in wasm builds.
+package witvalue
+
+import (
+	"fmt"
+)
+
+// NodeKind is the kind of a node, mirroring the cases of golem:rpc's wit-node
+type NodeKind int
+
+const (
+	NodeKindRecord NodeKind = iota
+	NodeKindVariant
+	NodeKindEnum
+	NodeKindFlags
+	NodeKindTuple
+	NodeKindList
+	NodeKindOption
+	NodeKindResult
+	NodeKindPrimU8
+	NodeKindPrimU16
+	NodeKindPrimU32
+	NodeKindPrimU64
+	NodeKindPrimS8
+	NodeKindPrimS16
+	NodeKindPrimS32
+	NodeKindPrimS64
+	NodeKindPrimFloat32
+	NodeKindPrimFloat64
+	NodeKindPrimChar
+	NodeKindPrimBool
+	NodeKindPrimString
+	NodeKindHandle
+)
+
+var nodeKindNames = map[NodeKind]string{
+	NodeKindRecord:      "record",
+	NodeKindVariant:     "variant",
+	NodeKindEnum:        "enum",
+	NodeKindFlags:       "flags",
+	NodeKindTuple:       "tuple",
+	NodeKindList:        "list",
+	NodeKindOption:      "option",
+	NodeKindResult:      "result",
+	NodeKindPrimU8:      "u8",
+	NodeKindPrimU16:     "u16",
+	NodeKindPrimU32:     "u32",
+	NodeKindPrimU64:     "u64",
+	NodeKindPrimS8:      "s8",
+	NodeKindPrimS16:     "s16",
+	NodeKindPrimS32:     "s32",
+	NodeKindPrimS64:     "s64",
+	NodeKindPrimFloat32: "float32",
+	NodeKindPrimFloat64: "float64",
+	NodeKindPrimChar:    "char",
+	NodeKindPrimBool:    "bool",
+	NodeKindPrimString:  "string",
+	NodeKindHandle:      "handle",
+}
+
+func (k NodeKind) String() string {
+	if name, ok := nodeKindNames[k]; ok {
+		return name
+	}
+	return fmt.Sprintf("NodeKind(%d)", int(k))
+}
+
+// NoChild is the Child of variants, options and results without payload
+const NoChild int32 = -1
+
+// Node is a node of a wit-value tree
+type Node struct {
+	Kind NodeKind
+	// Children are the node indexes of the items of records, tuples and lists
+	Children []int32
+	// Case is the case index of variants and enums
+	Case uint32
+	// Child is the node index of the payload of variants, options and results, or NoChild
+	Child int32
+	// IsErr is set for results of the error case
+	IsErr bool
+	// Flags are the values of flags
+	Flags []bool
+	// Prim is the value of primitive nodes, using the go types of the primitive types, and Char for char
+	Prim any
+	// Handle is the value of handles
+	Handle Handle
+}
+
+// Value is a wit-value tree, the root is the first node
+type Value struct {
+	Nodes []Node
+}
+
+func (v Value) node(index int32) (Node, error) {
+	if index < 0 || int(index) >= len(v.Nodes) {
+		return Node{}, fmt.Errorf("node index out of range: %d", index)
+	}
+	return v.Nodes[index], nil
+}
+
+// Char is a WIT char
+type Char rune
+
+// Enum is the case index of a WIT enum
+type Enum uint32
+
+// Flags are the values of WIT flags
+type Flags []bool
+
+// Handle is a resource handle owned by the worker with the URI
+type Handle struct {
+	URI        string
+	ResourceID uint64
+}
+
+// Record is a record of dynamic values
+type Record []any
+
+// Tuple is a tuple of dynamic values
+type Tuple []any
+
+// Variant is a case of a WIT variant, the Payload is nil for cases without payload
+type Variant struct {
+	Case    uint32
+	Payload any
+}
+
+// VariantMarshaler is implemented by go types representing WIT variants
+type VariantMarshaler interface {
+	MarshalWitVariant() (Variant, error)
+}
+
+// VariantUnmarshaler is implemented by pointers to go types representing WIT variants
+type VariantUnmarshaler interface {
+	UnmarshalWitVariant(caseIndex uint32, payload Payload) error
+}
+
+// Payload is the payload of an unmarshaled variant or result case, it can also be used as the type params of Result
+// to unmarshal the payload later
+type Payload struct {
+	value Value
+	index int32
+}
+
+// IsNone returns whether the case has no payload
+func (p Payload) IsNone() bool {
+	return p.index == NoChild
+}
+
+// Unmarshal unmarshals the payload into target, which must be a pointer
+func (p Payload) Unmarshal(target any) error {
+	if p.IsNone() {
+		return fmt.Errorf("missing payload")
+	}
+	return unmarshalNode(p.value, p.index, target)
+}
+
+// Result is a WIT result, with the Ok or Err payload set depending on IsErr. Payloads of type struct{} or nil
+// interfaces are mapped to cases without payload.
+type Result[T, E any] struct {
+	Ok    T
+	Err   E
+	IsErr bool
+}
+
+// Ok returns an ok Result
+func Ok[T, E any](value T) Result[T, E] {
+	return Result[T, E]{Ok: value}
+}
+
+// Err returns an error Result
+func Err[T, E any](err E) Result[T, E] {
+	return Result[T, E]{Err: err, IsErr: true}
+}
+
+func (r Result[T, E]) marshalWitResult() (bool, any) {
+	if r.IsErr {
+		return true, r.Err
+	}
+	return false, r.Ok
+}
+
+func (r *Result[T, E]) unmarshalWitResult(isErr bool, payload Payload) error {
+	*r = Result[T, E]{IsErr: isErr}
+	if isErr {
+		return unmarshalPayload(payload, &r.Err)
+	}
+	return unmarshalPayload(payload, &r.Ok)
+}
+
+type resultMarshaler interface {
+	marshalWitResult() (bool, any)
+}
+
+type resultUnmarshaler interface {
+	unmarshalWitResult(isErr bool, payload Payload) error
+}
@@ -0,0 +1,247 @@
+//go:build hosttest
+
+package witvalue
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type point struct {
+	X, Y   int32
+	Label  string
+	hidden bool
+	Cached float64 `wit:"-"`
+}
+
+type shape struct {
+	Name   string
+	Points []point
+	Origin *point
+	Tags   [2]string
+}
+
+// event is a variant with the cases created(u64), deleted and renamed(string)
+type event struct {
+	caseIndex uint32
+	id        uint64
+	name      string
+}
+
+func (e event) MarshalWitVariant() (Variant, error) {
+	switch e.caseIndex {
+	case 0:
+		return Variant{Case: 0, Payload: e.id}, nil
+	case 1:
+		return Variant{Case: 1}, nil
+	case 2:
+		return Variant{Case: 2, Payload: e.name}, nil
+	default:
+		return Variant{}, errors.New("invalid case")
+	}
+}
+
+func (e *event) UnmarshalWitVariant(caseIndex uint32, payload Payload) error {
+	*e = event{caseIndex: caseIndex}
+	switch caseIndex {
+	case 0:
+		return payload.Unmarshal(&e.id)
+	case 1:
+		return nil
+	case 2:
+		return payload.Unmarshal(&e.name)
+	default:
+		return errors.New("invalid case")
+	}
+}
+
+func TestRoundTrip(t *testing.T) {
+	tests := []struct {
+		name  string
+		value any
+	}{
+		{name: "bool", value: true},
+		{name: "u8", value: uint8(8)},
+		{name: "u16", value: uint16(16)},
+		{name: "u32", value: uint32(32)},
+		{name: "u64", value: uint64(64)},
+		{name: "s8", value: int8(-8)},
+		{name: "s16", value: int16(-16)},
+		{name: "s32", value: int32(-32)},
+		{name: "s64", value: int64(-64)},
+		{name: "int", value: -1},
+		{name: "uint", value: uint(1)},
+		{name: "float32", value: float32(1.5)},
+		{name: "float64", value: 2.5},
+		{name: "char", value: Char('é')},
+		{name: "string", value: "hello"},
+		{name: "record", value: point{X: 1, Y: -2, Label: "a"}},
+		{name: "nested", value: shape{
+			Name:   "triangle",
+			Points: []point{{X: 0, Y: 0}, {X: 1, Y: 0}, {X: 0, Y: 1}},
+			Origin: &point{X: 3},
+			Tags:   [2]string{"a", "b"},
+		}},
+		{name: "empty list", value: []uint64{}},
+		{name: "some", value: ptr(uint64(1))},
+		{name: "none", value: (*uint64)(nil)},
+		{name: "some none", value: ptr((*string)(nil))},
+		{name: "ok", value: Ok[uint64, string](1)},
+		{name: "err", value: Err[uint64, string]("failed")},
+		{name: "unit ok", value: Ok[struct{}, string](struct{}{})},
+		{name: "variant", value: event{caseIndex: 2, name: "renamed"}},
+		{name: "variant without payload", value: event{caseIndex: 1}},
+		{name: "list of variants", value: []event{{caseIndex: 0, id: 1}, {caseIndex: 1}}},
+		{name: "enum", value: Enum(3)},
+		{name: "flags", value: Flags{true, false, true}},
+		{name: "handle", value: Handle{URI: "urn:worker:x/y", ResourceID: 7}},
+		{name: "tuple", value: Tuple{uint64(1), "a", Record{true}}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			value, err := Marshal(test.value)
+			if err != nil {
+				t.Fatalf("%+v", err)
+			}
+
+			target := reflect.New(reflect.TypeOf(test.value))
+			err = Unmarshal(value, target.Interface())
+			if err != nil {
+				t.Fatalf("%+v", err)
+			}
+
+			expected := test.value
+			if p, ok := expected.(point); ok {
+				p.Cached = 0
+				expected = p
+			}
+			if actual := target.Elem().Interface(); !reflect.DeepEqual(actual, expected) {
+				t.Fatalf("Expected: %#v, actual: %#v", expected, actual)
+			}
+		})
+	}
+}
+
+func TestMarshalSkipsUnexportedAndIgnoredFields(t *testing.T) {
+	value, err := Marshal(point{X: 1, Y: 2, Label: "a", hidden: true, Cached: 3})
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	if len(value.Nodes[0].Children) != 3 {
+		t.Fatalf("Expected 3 record fields, actual: %d", len(value.Nodes[0].Children))
+	}
+}
+
+func TestUnmarshalDynamic(t *testing.T) {
+	value, err := Marshal(shape{
+		Name:   "line",
+		Points: []point{{X: 1, Y: 2, Label: "a"}},
+		Tags:   [2]string{"a", "b"},
+	})
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+
+	var actual any
+	err = Unmarshal(value, &actual)
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+
+	expected := Record{
+		"line",
+		[]any{Record{int32(1), int32(2), "a"}},
+		nil,
+		[]any{"a", "b"},
+	}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Fatalf("Expected: %#v, actual: %#v", expected, actual)
+	}
+
+	value, err = Marshal(Tuple{Err[struct{}, event](event{caseIndex: 0, id: 5}), Char('x')})
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	err = Unmarshal(value, &actual)
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	expectedTuple := Tuple{Err[any, any](Variant{Case: 0, Payload: uint64(5)}), Char('x')}
+	if !reflect.DeepEqual(actual, expectedTuple) {
+		t.Fatalf("Expected: %#v, actual: %#v", expectedTuple, actual)
+	}
+}
+
+func TestUnmarshalTuple(t *testing.T) {
+	value, err := Marshal(Tuple{uint64(1), point{X: 1, Y: 2, Label: "a"}})
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+
+	var count uint64
+	var p point
+	err = UnmarshalTuple(value, &count, &p)
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	if count != 1 || p != (point{X: 1, Y: 2, Label: "a"}) {
+		t.Fatalf("Unexpected results: %d, %#v", count, p)
+	}
+
+	err = UnmarshalTuple(value, &count)
+	expectErrorContains(t, err, "expected 1 item(s), got 2")
+}
+
+func TestErrors(t *testing.T) {
+	_, err := Marshal(map[string]int{})
+	expectErrorContains(t, err, "unsupported type: map[string]int")
+
+	_, err = Marshal([]any{nil})
+	expectErrorContains(t, err, "item #0: unsupported nil value")
+
+	_, err = Marshal(event{caseIndex: 9})
+	expectErrorContains(t, err, "invalid case")
+
+	value, err := Marshal(uint64(1))
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+
+	var s string
+	expectErrorContains(t, Unmarshal(value, &s), "expected string, got u64")
+	expectErrorContains(t, Unmarshal(value, s), "target must be a non-nil pointer")
+
+	var p point
+	value, err = Marshal(Record{int32(1)})
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	expectErrorContains(t, Unmarshal(value, &p), "expected 3 field(s), got 1")
+
+	var ok uint64
+	expectErrorContains(t, Unmarshal(Value{Nodes: []Node{{Kind: NodeKindOption, Child: 5}}}, ptr(&ok)), "node index out of range: 5")
+
+	var result Result[uint64, string]
+	value, err = Marshal(Ok[struct{}, string](struct{}{}))
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	expectErrorContains(t, Unmarshal(value, &result), "missing payload for uint64")
+}
+
+func ptr[T any](value T) *T {
+	return &value
+}
+
+func expectErrorContains(t *testing.T, err error, expected string) {
+	t.Helper()
+	if err == nil {
+		t.Fatalf("Expected error containing %q", expected)
+	}
+	if !strings.Contains(err.Error(), expected) {
+		t.Fatalf("Expected error containing %q, actual: %q", expected, err.Error())
+	}
+}
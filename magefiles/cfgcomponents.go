@@ -0,0 +1,165 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+var cfgComponentsFile = filepath.Join(libDir, "cfg", "components_gen.go")
+
+// cfgComponentTableFile is the cgo free package of the env var names and the dependencies, which can also be used by
+// the host tools and the integration tests
+var cfgComponentTableFile = filepath.Join(libDir, "cfg", "components", "components_gen.go")
+
+// GenerateCfgComponents generates the component id and routing env var names and the dependencies into
+// lib/cfg/components, and the component id and worker URI accessors of lib/cfg for all components
+func GenerateCfgComponents() error {
+	for _, generated := range []struct {
+		file   string
+		source func(componentNames []string) ([]byte, error)
+	}{
+		{cfgComponentTableFile, cfgComponentTableSource},
+		{cfgComponentsFile, cfgComponentsSource},
+	} {
+		fmt.Printf("Generating cfg component accessors into %s\n", generated.file)
+
+		src, err := generated.source(componentNames())
+		if err != nil {
+			return fmt.Errorf("generate cfg components: %w", err)
+		}
+
+		err = os.MkdirAll(filepath.Dir(generated.file), 0755)
+		if err != nil {
+			return fmt.Errorf("generate cfg components: mkdir failed for %s, %w", generated.file, err)
+		}
+		err = os.WriteFile(generated.file, src, 0644)
+		if err != nil {
+			return fmt.Errorf("generate cfg components: write file failed for %s, %w", generated.file, err)
+		}
+	}
+
+	return nil
+}
+
+func cfgComponentTableSource(componentNames []string) ([]byte, error) {
+	buf := &bytes.Buffer{}
+	buf.WriteString("// Code generated by the generateCfgComponents magefile command. DO NOT EDIT.\n\n")
+	buf.WriteString("// Package components contains the env var names and the worker to worker RPC dependencies of the components. It\n")
+	buf.WriteString("// does not depend on the golem bindings, so it can also be used on the host, e.g. by the tools and integration tests.\n")
+	buf.WriteString("package components\n\n")
+
+	buf.WriteString("// The env vars of the component ids, the workers calling a component need its id in the env var\n")
+	buf.WriteString("const (\n")
+	for _, componentName := range componentNames {
+		_, _ = fmt.Fprintf(buf, "%sIDEnvVar = %q\n", dashToPascal(componentName), componentIDEnvVar(componentName))
+	}
+	buf.WriteString(")\n\n")
+
+	buf.WriteString("// The env vars of the routings of the components as dependencies, see cfg.ComponentRouting\n")
+	buf.WriteString("const (\n")
+	for _, componentName := range componentNames {
+		_, _ = fmt.Fprintf(buf, "%sRoutingEnvVar = %q\n", dashToPascal(componentName), componentRoutingEnvVar(componentName))
+	}
+	buf.WriteString(")\n\n")
+
+	buf.WriteString("// IDEnvVars are the env vars of the component ids by component name\n")
+	buf.WriteString("var IDEnvVars = map[string]string{\n")
+	for _, componentName := range componentNames {
+		_, _ = fmt.Fprintf(buf, "%q: %sIDEnvVar,\n", componentName, dashToPascal(componentName))
+	}
+	buf.WriteString("}\n\n")
+
+	buf.WriteString("// RoutingEnvVars are the env vars of the routings by component name\n")
+	buf.WriteString("var RoutingEnvVars = map[string]string{\n")
+	for _, componentName := range componentNames {
+		_, _ = fmt.Fprintf(buf, "%q: %sRoutingEnvVar,\n", componentName, dashToPascal(componentName))
+	}
+	buf.WriteString("}\n\n")
+
+	buf.WriteString("// Dependencies are the worker to worker RPC dependencies by component name\n")
+	buf.WriteString("var Dependencies = map[string][]string{\n")
+	for _, componentName := range componentNames {
+		dependencies := componentDeps[componentName]
+		if len(dependencies) == 0 {
+			continue
+		}
+		_, _ = fmt.Fprintf(buf, "%q: {", componentName)
+		for i, dependency := range dependencies {
+			if i > 0 {
+				buf.WriteString(", ")
+			}
+			_, _ = fmt.Fprintf(buf, "%q", dependency)
+		}
+		buf.WriteString("},\n")
+	}
+	buf.WriteString("}\n")
+
+	src, err := format.Source(buf.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("format failed, %w", err)
+	}
+	return src, nil
+}
+
+func cfgComponentsSource(componentNames []string) ([]byte, error) {
+	buf := &bytes.Buffer{}
+	buf.WriteString("// Code generated by the generateCfgComponents magefile command. DO NOT EDIT.\n\n")
+	buf.WriteString("package cfg\n\n")
+	buf.WriteString("import \"golem-go-project/lib/cfg/components\"\n\n")
+
+	buf.WriteString("// The env vars of the component ids, see components.IDEnvVars\n")
+	buf.WriteString("const (\n")
+	for _, componentName := range componentNames {
+		_, _ = fmt.Fprintf(buf, "%sIDEnvVar = components.%sIDEnvVar\n", dashToPascal(componentName), dashToPascal(componentName))
+	}
+	buf.WriteString(")\n\n")
+
+	buf.WriteString("// The env vars of the routings of the components as dependencies, see ComponentRouting\n")
+	buf.WriteString("const (\n")
+	for _, componentName := range componentNames {
+		_, _ = fmt.Fprintf(buf, "%sRoutingEnvVar = components.%sRoutingEnvVar\n", dashToPascal(componentName), dashToPascal(componentName))
+	}
+	buf.WriteString(")\n\n")
+
+	buf.WriteString("// ComponentIDEnvVars are the env vars of the component ids by component name\n")
+	buf.WriteString("var ComponentIDEnvVars = components.IDEnvVars\n\n")
+	buf.WriteString("// ComponentRoutingEnvVars are the env vars of the routings by component name\n")
+	buf.WriteString("var ComponentRoutingEnvVars = components.RoutingEnvVars\n\n")
+	buf.WriteString("// ComponentDependencies are the worker to worker RPC dependencies by component name\n")
+	buf.WriteString("var ComponentDependencies = components.Dependencies\n")
+
+	for _, componentName := range componentNames {
+		componentPascal := dashToPascal(componentName)
+		_, _ = fmt.Fprintf(buf, "\n// %sID returns the id of %s from the %s env var\n", componentPascal, componentName, componentIDEnvVar(componentName))
+		_, _ = fmt.Fprintf(buf, "func %sID() (ComponentID, error) {\n", componentPascal)
+		_, _ = fmt.Fprintf(buf, "return ComponentIDFromEnv(%sIDEnvVar)\n}\n", componentPascal)
+
+		_, _ = fmt.Fprintf(buf, "\n// %sWorkerURI returns the URI of the %s worker with the name\n", componentPascal, componentName)
+		_, _ = fmt.Fprintf(buf, "func %sWorkerURI(workerName string) (URI, error) {\n", componentPascal)
+		_, _ = fmt.Fprintf(buf, "return workerURIF(%sID, workerName)\n}\n", componentPascal)
+
+		_, _ = fmt.Fprintf(buf, "\n// %sRoutedWorkerURI returns the URI of the %s worker routed for the key, see ComponentRouting\n", componentPascal, componentName)
+		_, _ = fmt.Fprintf(buf, "func %sRoutedWorkerURI(key string) (URI, error) {\n", componentPascal)
+		_, _ = fmt.Fprintf(buf, "return routedWorkerURIF(%q, %sRoutingEnvVar, %sID, key)\n}\n", componentName, componentPascal, componentPascal)
+	}
+
+	src, err := format.Source(buf.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("format failed, %w", err)
+	}
+	return src, nil
+}
+
+// componentRoutingEnvVar returns the name of the env var of the component's routing, e.g. COMPONENT_ONE_ROUTING
+func componentRoutingEnvVar(componentName string) string {
+	return strings.ToUpper(strings.ReplaceAll(componentName, "-", "_")) + "_ROUTING"
+}
+
+// componentIDEnvVar returns the name of the env var of the component's id, e.g. COMPONENT_ONE_ID for component-one
+func componentIDEnvVar(componentName string) string {
+	return strings.ToUpper(strings.ReplaceAll(componentName, "-", "_")) + "_ID"
+}
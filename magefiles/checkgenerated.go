@@ -0,0 +1,296 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/magefile/mage/sh"
+)
+
+// checkGeneratedMaxHunkLines limits the number of lines shown per side of a changed file's diff
+var checkGeneratedMaxHunkLines = 10
+
+// CheckGenerated regenerates bindings, stub WIT dependencies, RPC clients and cfg component accessors into a temp dir,
+// and fails if they differ from the working tree
+func CheckGenerated() error {
+	deps, err := verifiedWitDeps()
+	if err != nil {
+		return fmt.Errorf("check generated: %w", err)
+	}
+	// the drifted wit/deps are only reported, so the working tree is not changed
+	diffs, err := componentWitDepsDrifts(deps)
+	if err != nil {
+		return fmt.Errorf("check generated: %w", err)
+	}
+
+	tmpDir, err := os.MkdirTemp("", "check-generated-")
+	if err != nil {
+		return fmt.Errorf("check generated: create temp dir failed, %w", err)
+	}
+	defer func() { _ = os.RemoveAll(tmpDir) }()
+
+	for _, componentName := range componentNames() {
+		witDir := filepath.Join(componentsDir, componentName, "wit")
+		tmpComponentDir := filepath.Join(tmpDir, "binding", componentName)
+		bindings := componentBindings(componentName)
+
+		for _, bindingWorld := range bindings {
+			err := generateBinding(witDir, bindingWorld, bindingWorld.dir(tmpComponentDir))
+			if err != nil {
+				return fmt.Errorf("check generated: binding generation failed for %s (%s), %w", componentName, bindingWorld.World, err)
+			}
+		}
+
+		// the extra binding packages are nested into the main one, so comparing the main binding dir covers all of them
+		bindingDiffs, err := diffDirs(bindings[0].dir(tmpComponentDir), bindings[0].dir(filepath.Join(componentsDir, componentName)))
+		if err != nil {
+			return fmt.Errorf("check generated: %w", err)
+		}
+		diffs = append(diffs, bindingDiffs...)
+	}
+
+	for _, componentName := range stubComponentNames() {
+		srcWitDir := filepath.Join(componentsDir, componentName, "wit")
+		stubDir := filepath.Join(tmpDir, "stub", componentName)
+
+		err := sh.RunV(
+			"golem-cli", "stubgen", "generate",
+			"--source-wit-root", srcWitDir,
+			"--dest-crate-root", stubDir,
+		)
+		if err != nil {
+			return fmt.Errorf("check generated: stub generation failed for %s, %w", componentName, err)
+		}
+	}
+
+	for _, componentName := range componentNames() {
+		witDir := filepath.Join(componentsDir, componentName, "wit")
+		tmpWitDir := filepath.Join(tmpDir, "wit", componentName)
+
+		err := copyDir(witDir, tmpWitDir)
+		if err != nil {
+			return fmt.Errorf("check generated: %w", err)
+		}
+		err = removeStubWitDeps(tmpWitDir)
+		if err != nil {
+			return fmt.Errorf("check generated: %w", err)
+		}
+
+		for _, dependency := range componentDeps[componentName] {
+			err := sh.RunV(
+				"golem-cli", "stubgen", "add-stub-dependency",
+				"--overwrite",
+				"--stub-wit-root", filepath.Join(tmpDir, "stub", dependency, "wit"),
+				"--dest-wit-root", tmpWitDir,
+			)
+			if err != nil {
+				return fmt.Errorf("check generated: add stub dependency failed for %s to %s, %w", dependency, componentName, err)
+			}
+		}
+
+		stubDepNames, err := stubWitDepNames(filepath.Join(tmpWitDir, "deps"), filepath.Join(witDir, "deps"))
+		if err != nil {
+			return fmt.Errorf("check generated: %w", err)
+		}
+		for _, stubDepName := range stubDepNames {
+			stubDiffs, err := diffDirs(
+				filepath.Join(tmpWitDir, "deps", stubDepName),
+				filepath.Join(witDir, "deps", stubDepName),
+			)
+			if err != nil {
+				return fmt.Errorf("check generated: %w", err)
+			}
+			diffs = append(diffs, stubDiffs...)
+		}
+	}
+
+	tmpClientsDir := filepath.Join(tmpDir, "clients")
+	for _, componentName := range stubComponentNames() {
+		err := generateClient(componentName, tmpClientsDir)
+		if err != nil {
+			return fmt.Errorf("check generated: %w", err)
+		}
+	}
+	for _, componentName := range stubComponentNames() {
+		pkg := clientPackageName(componentName)
+		clientDiffs, err := diffDirs(filepath.Join(tmpClientsDir, pkg), filepath.Join(clientsDir, pkg))
+		if err != nil {
+			return fmt.Errorf("check generated: %w", err)
+		}
+		diffs = append(diffs, clientDiffs...)
+	}
+
+	for _, generated := range []struct {
+		file   string
+		source func(componentNames []string) ([]byte, error)
+	}{
+		{cfgComponentTableFile, cfgComponentTableSource},
+		{cfgComponentsFile, cfgComponentsSource},
+	} {
+		cfgComponents, err := generated.source(componentNames())
+		if err != nil {
+			return fmt.Errorf("check generated: %w", err)
+		}
+		actualCfgComponents, err := os.ReadFile(generated.file)
+		switch {
+		case os.IsNotExist(err):
+			diffs = append(diffs, fmt.Sprintf("missing: %s", generated.file))
+		case err != nil:
+			return fmt.Errorf("check generated: read failed for %s, %w", generated.file, err)
+		case !bytes.Equal(cfgComponents, actualCfgComponents):
+			diffs = append(diffs, fmt.Sprintf("changed: %s\n%s", generated.file, diffLines(string(cfgComponents), string(actualCfgComponents))))
+		}
+	}
+
+	if len(diffs) > 0 {
+		for _, diff := range diffs {
+			fmt.Println(diff)
+		}
+		return fmt.Errorf("check generated: %d generated path(s) are out of date, run syncWitDeps, updateRpcStubs, generateCfgComponents and build", len(diffs))
+	}
+
+	fmt.Println("Generated bindings, stub WIT dependencies, RPC clients and cfg component accessors are up to date")
+	return nil
+}
+
+// removeStubWitDeps removes the stub WIT dependencies added by add-stub-dependency from the wit dir
+func removeStubWitDeps(witDir string) error {
+	stubDepDirs, err := filepath.Glob(filepath.Join(witDir, "deps", packageOrg+"_*"))
+	if err != nil {
+		return fmt.Errorf("glob failed for %s, %w", witDir, err)
+	}
+	for _, stubDepDir := range stubDepDirs {
+		err := os.RemoveAll(stubDepDir)
+		if err != nil {
+			return fmt.Errorf("remove all failed for %s, %w", stubDepDir, err)
+		}
+	}
+	return nil
+}
+
+// stubWitDepNames returns the sorted union of the stub WIT dependency dir names in the deps dirs
+func stubWitDepNames(depsDirs ...string) ([]string, error) {
+	names := map[string]string{}
+	for _, depsDir := range depsDirs {
+		stubDepDirs, err := filepath.Glob(filepath.Join(depsDir, packageOrg+"_*"))
+		if err != nil {
+			return nil, fmt.Errorf("glob failed for %s, %w", depsDir, err)
+		}
+		for _, stubDepDir := range stubDepDirs {
+			names[filepath.Base(stubDepDir)] = stubDepDir
+		}
+	}
+	return sortedKeys(names), nil
+}
+
+// diffDirs compares the freshly generated expectedDir with actualDir from the working tree,
+// and returns a readable summary of the missing, stale and changed files
+func diffDirs(expectedDir, actualDir string) ([]string, error) {
+	if _, err := os.Stat(actualDir); os.IsNotExist(err) {
+		return []string{fmt.Sprintf("missing: %s", actualDir)}, nil
+	}
+	if _, err := os.Stat(expectedDir); os.IsNotExist(err) {
+		return []string{fmt.Sprintf("stale: %s", actualDir)}, nil
+	}
+
+	expectedFiles, err := relFilePaths(expectedDir)
+	if err != nil {
+		return nil, err
+	}
+	actualFiles, err := relFilePaths(actualDir)
+	if err != nil {
+		return nil, err
+	}
+
+	relPaths := map[string]string{}
+	for relPath := range expectedFiles {
+		relPaths[relPath] = relPath
+	}
+	for relPath := range actualFiles {
+		relPaths[relPath] = relPath
+	}
+
+	var diffs []string
+	for _, relPath := range sortedKeys(relPaths) {
+		actualPath := filepath.Join(actualDir, relPath)
+		switch {
+		case !actualFiles[relPath]:
+			diffs = append(diffs, fmt.Sprintf("missing: %s", actualPath))
+		case !expectedFiles[relPath]:
+			diffs = append(diffs, fmt.Sprintf("stale: %s", actualPath))
+		default:
+			expected, err := os.ReadFile(filepath.Join(expectedDir, relPath))
+			if err != nil {
+				return nil, fmt.Errorf("diffDirs: read failed for %s, %w", relPath, err)
+			}
+			actual, err := os.ReadFile(actualPath)
+			if err != nil {
+				return nil, fmt.Errorf("diffDirs: read failed for %s, %w", actualPath, err)
+			}
+			if !bytes.Equal(expected, actual) {
+				diffs = append(diffs, fmt.Sprintf("changed: %s\n%s", actualPath, diffLines(string(expected), string(actual))))
+			}
+		}
+	}
+
+	return diffs, nil
+}
+
+func relFilePaths(dir string) (map[string]bool, error) {
+	paths := map[string]bool{}
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			return nil
+		}
+		relPath, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		paths[relPath] = true
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("relFilePaths: walk failed for %s, %w", dir, err)
+	}
+	return paths, nil
+}
+
+// diffLines returns the changed region between expected and actual, after trimming their common leading and trailing lines,
+// lines only in the working tree are prefixed with -, lines only in the regenerated output with +
+func diffLines(expected, actual string) string {
+	expectedLines := strings.Split(expected, "\n")
+	actualLines := strings.Split(actual, "\n")
+
+	prefix := 0
+	for prefix < len(expectedLines) && prefix < len(actualLines) && expectedLines[prefix] == actualLines[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(expectedLines)-prefix && suffix < len(actualLines)-prefix &&
+		expectedLines[len(expectedLines)-1-suffix] == actualLines[len(actualLines)-1-suffix] {
+		suffix++
+	}
+
+	var diff strings.Builder
+	_, _ = fmt.Fprintf(&diff, "  @@ line %d @@\n", prefix+1)
+	writeHunk := func(sign string, lines []string) {
+		for i, line := range lines {
+			if i == checkGeneratedMaxHunkLines {
+				_, _ = fmt.Fprintf(&diff, "  %s ... %d more line(s)\n", sign, len(lines)-i)
+				break
+			}
+			_, _ = fmt.Fprintf(&diff, "  %s %s\n", sign, line)
+		}
+	}
+	writeHunk("-", actualLines[prefix:len(actualLines)-suffix])
+	writeHunk("+", expectedLines[prefix:len(expectedLines)-suffix])
+
+	return strings.TrimSuffix(diff.String(), "\n")
+}
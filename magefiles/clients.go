@@ -0,0 +1,278 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"golem-go-project/tools/wit"
+)
+
+var clientsDir = filepath.Join(libDir, "clients")
+
+// clientGoTypes maps the WIT types supported by the generated clients to go types and their zero values
+var clientGoTypes = map[string][2]string{
+	"bool":    {"bool", "false"},
+	"u8":      {"uint8", "0"},
+	"u16":     {"uint16", "0"},
+	"u32":     {"uint32", "0"},
+	"u64":     {"uint64", "0"},
+	"s8":      {"int8", "0"},
+	"s16":     {"int16", "0"},
+	"s32":     {"int32", "0"},
+	"s64":     {"int64", "0"},
+	"f32":     {"float32", "0"},
+	"float32": {"float32", "0"},
+	"f64":     {"float64", "0"},
+	"float64": {"float64", "0"},
+	"string":  {"string", `""`},
+}
+
+// clientReservedNames are the identifiers used by the generated methods, params with these names get a suffix
+var clientReservedNames = map[string]bool{
+	"c": true, "ctx": true, "err": true, "results": true, "rpc": true, "cfg": true, "fmt": true, "context": true,
+	"break": true, "case": true, "chan": true, "const": true, "continue": true, "default": true, "defer": true,
+	"else": true, "fallthrough": true, "for": true, "func": true, "go": true, "goto": true, "if": true,
+	"import": true, "interface": true, "map": true, "package": true, "range": true, "return": true, "select": true,
+	"struct": true, "switch": true, "type": true, "var": true,
+}
+
+// clientErrorCases are the cases of the error variant, which is returned as *rpc.Error by the generated clients
+var clientErrorCases = []string{"protocol-error", "denied", "not-found", "remote-internal-error", "config-error"}
+
+// GenerateClients generates the typed RPC client packages into lib/clients for the components used as dependencies
+func GenerateClients() error {
+	for _, componentName := range stubComponentNames() {
+		err := generateClient(componentName, clientsDir)
+		if err != nil {
+			return fmt.Errorf("generate clients: %w", err)
+		}
+	}
+	return nil
+}
+
+func clientPackageName(componentName string) string {
+	return strings.ReplaceAll(componentName, "-", "")
+}
+
+func generateClient(componentName, dstClientsDir string) error {
+	witFiles, err := filepath.Glob(filepath.Join(componentsDir, componentName, "wit", "*.wit"))
+	if err != nil {
+		return fmt.Errorf("glob failed for %s, %w", componentName, err)
+	}
+
+	apiInterfaceName := componentName + "-api"
+	var apiInterface *wit.Interface
+	var packageName *wit.PackageName
+	for _, witFile := range witFiles {
+		file, err := wit.ParseFile(witFile)
+		if err != nil {
+			return err
+		}
+		if iface := file.Interface(apiInterfaceName); iface != nil {
+			apiInterface = iface
+			packageName = file.Package
+		}
+	}
+	if apiInterface == nil || packageName == nil {
+		return fmt.Errorf("missing %s interface for %s", apiInterfaceName, componentName)
+	}
+
+	pkg := clientPackageName(componentName)
+	componentPascal := dashToPascal(componentName)
+
+	buf := &bytes.Buffer{}
+	_, _ = fmt.Fprintf(buf, "// Client invokes the functions of a %s worker\n", componentName)
+	buf.WriteString("type Client struct {\n// workerName is the name of the worker, or the routing key of routed clients\nworkerName string\nrouted bool\n}\n\n")
+	_, _ = fmt.Fprintf(buf, "// Worker returns a client for the %s worker with the name\n", componentName)
+	buf.WriteString("func Worker(workerName string) *Client {\nreturn &Client{workerName: workerName}\n}\n\n")
+	_, _ = fmt.Fprintf(buf, "// Routed returns a client for the %s worker selected by its routing for the key, see cfg.ComponentRouting\n", componentName)
+	buf.WriteString("func Routed(key string) *Client {\nreturn &Client{workerName: key, routed: true}\n}\n")
+
+	resultErrorSupported := isClientErrorVariant(apiInterface.TypeDef("error"))
+	var usesInvoke, usesInvokeResult, usesFireAndForget bool
+	for _, f := range apiInterface.Funcs {
+		functionName := fmt.Sprintf("%s:%s/%s.{%s}", packageName.Namespace, packageName.Name, apiInterfaceName, f.Name)
+
+		var params, args []string
+		for _, param := range f.Params {
+			goType, ok := clientGoTypes[param.Type.String()]
+			if !ok {
+				return fmt.Errorf("%s: unsupported param type for generated clients: %s", param.Pos, param.Type)
+			}
+			name := dashToCamel(param.Name)
+			if clientReservedNames[name] {
+				name += "Param"
+			}
+			params = append(params, fmt.Sprintf("%s %s", name, goType[0]))
+			args = append(args, name)
+		}
+
+		methodName := dashToPascal(f.Name)
+		invokeArgs := strings.Join(append([]string{"ctx", fmt.Sprintf("%q", functionName)}, args...), ", ")
+		signature := strings.Join(append([]string{"ctx context.Context"}, params...), ", ")
+
+		if len(f.Results) > 1 {
+			return fmt.Errorf("%s: multiple results are not supported by generated clients: %s", f.Pos, f.Name)
+		}
+		var result *wit.Type
+		if len(f.Results) == 1 {
+			if f.Results[0].Name != "" {
+				return fmt.Errorf("%s: named results are not supported by generated clients: %s", f.Results[0].Pos, f.Name)
+			}
+			result = f.Results[0].Type
+		}
+
+		_, _ = fmt.Fprintf(buf, "\n// %s invokes %s\n", methodName, functionName)
+		isResult := result != nil && result.Name == "result" && len(result.Params) == 2 && result.Params[1].String() == "error"
+		switch {
+		case isResult:
+			if !resultErrorSupported {
+				return fmt.Errorf(
+					"%s: unsupported result type for generated clients, the error variant must have the cases %s: %s",
+					result.Pos, strings.Join(clientErrorCases, ", "), result,
+				)
+			}
+			usesInvokeResult = true
+			resultArgs := strings.Join(append([]string{"ctx", fmt.Sprintf("%q", functionName), "nil"}, args...), ", ")
+			if result.Params[0] == nil {
+				_, _ = fmt.Fprintf(buf, "func (c *Client) %s(%s) error {\n", methodName, signature)
+				_, _ = fmt.Fprintf(buf, "return c.invokeResult(%s)\n}\n", resultArgs)
+				break
+			}
+			goType, ok := clientGoTypes[result.Params[0].String()]
+			if !ok {
+				return fmt.Errorf("%s: unsupported result type for generated clients: %s", result.Pos, result)
+			}
+			resultArgs = strings.Join(append([]string{"ctx", fmt.Sprintf("%q", functionName), "&ok"}, args...), ", ")
+			_, _ = fmt.Fprintf(buf, "func (c *Client) %s(%s) (%s, error) {\n", methodName, signature, goType[0])
+			_, _ = fmt.Fprintf(buf, "var ok %s\nerr := c.invokeResult(%s)\nreturn ok, err\n}\n", goType[0], resultArgs)
+		case result == nil:
+			usesInvoke = true
+			_, _ = fmt.Fprintf(buf, "func (c *Client) %s(%s) error {\n", methodName, signature)
+			_, _ = fmt.Fprintf(buf, "_, err := c.invoke(%s)\nreturn err\n}\n", invokeArgs)
+		default:
+			goType, ok := clientGoTypes[result.String()]
+			if !ok {
+				return fmt.Errorf("%s: unsupported result type for generated clients: %s", result.Pos, result)
+			}
+			usesInvoke = true
+			_, _ = fmt.Fprintf(buf, "func (c *Client) %s(%s) (%s, error) {\n", methodName, signature, goType[0])
+			_, _ = fmt.Fprintf(buf, "results, err := c.invoke(%s)\n", invokeArgs)
+			_, _ = fmt.Fprintf(buf, "if err != nil {\nreturn %s, err\n}\n", goType[1])
+			_, _ = fmt.Fprintf(buf, "return rpc.Result[%s](results, 0)\n}\n", goType[0])
+		}
+
+		if result == nil || isResult && result.Params[0] == nil {
+			usesFireAndForget = true
+			_, _ = fmt.Fprintf(buf, "\n// %sFireAndForget invokes %s without waiting for it to complete, delivery errors are logged\n", methodName, functionName)
+			_, _ = fmt.Fprintf(buf, "func (c *Client) %sFireAndForget(%s) {\n", methodName, signature)
+			_, _ = fmt.Fprintf(buf, "c.invokeFireAndForget(%s)\n}\n", invokeArgs)
+		}
+
+		asyncInvoke := "invokeAsync"
+		if isResult {
+			asyncInvoke = "invokeResultAsync"
+		}
+		_, _ = fmt.Fprintf(buf, "\n// %sAsync starts invoking %s, see rpc.AwaitAll\n", methodName, functionName)
+		_, _ = fmt.Fprintf(buf, "func (c *Client) %sAsync(%s) *rpc.Future {\n", methodName, signature)
+		_, _ = fmt.Fprintf(buf, "return c.%s(%s)\n}\n", asyncInvoke, invokeArgs)
+	}
+
+	buf.WriteString("\nfunc (c *Client) workerURI() (cfg.URI, error) {\n")
+	_, _ = fmt.Fprintf(buf, "if c.routed {\nreturn cfg.%sRoutedWorkerURI(c.workerName)\n}\n", componentPascal)
+	_, _ = fmt.Fprintf(buf, "return cfg.%sWorkerURI(c.workerName)\n}\n", componentPascal)
+
+	workerURI := func(errResult string) {
+		buf.WriteString("workerURI, err := c.workerURI()\n")
+		_, _ = fmt.Fprintf(buf, "if err != nil {\nreturn %s\n}\n", fmt.Sprintf(errResult, fmt.Sprintf("fmt.Errorf(\"%s: %%w\", err)", pkg)))
+	}
+	wrapErr := fmt.Sprintf("fmt.Errorf(\"%s: %%s failed for %%s, %%w\", functionName, c.workerName, err)", pkg)
+
+	if usesInvoke {
+		buf.WriteString("\nfunc (c *Client) invoke(ctx context.Context, functionName string, params ...any) ([]any, error) {\n")
+		workerURI("nil, %s")
+		buf.WriteString("results, err := rpc.InvokeAndAwait(ctx, workerURI, functionName, params...)\n")
+		_, _ = fmt.Fprintf(buf, "if err != nil {\nreturn nil, %s\n}\n", wrapErr)
+		buf.WriteString("return results, nil\n}\n")
+
+		buf.WriteString("\nfunc (c *Client) invokeAsync(ctx context.Context, functionName string, params ...any) *rpc.Future {\n")
+		workerURI("rpc.FailedFuture(functionName, %s)")
+		buf.WriteString("return rpc.AsyncInvokeAndAwait(ctx, workerURI, functionName, params...).WrapErr(func(err error) error {\n")
+		_, _ = fmt.Fprintf(buf, "return %s\n})\n}\n", wrapErr)
+	}
+
+	if usesInvokeResult {
+		buf.WriteString("\nfunc (c *Client) invokeResult(ctx context.Context, functionName string, ok any, params ...any) error {\n")
+		workerURI("%s")
+		buf.WriteString("err = rpc.InvokeAndAwaitResult(ctx, workerURI, functionName, ok, params...)\n")
+		_, _ = fmt.Fprintf(buf, "if err != nil {\nreturn %s\n}\n", wrapErr)
+		buf.WriteString("return nil\n}\n")
+
+		buf.WriteString("\nfunc (c *Client) invokeResultAsync(ctx context.Context, functionName string, params ...any) *rpc.Future {\n")
+		workerURI("rpc.FailedFuture(functionName, %s)")
+		buf.WriteString("return rpc.AsyncInvokeAndAwaitResult(ctx, workerURI, functionName, params...).WrapErr(func(err error) error {\n")
+		_, _ = fmt.Fprintf(buf, "return %s\n})\n}\n", wrapErr)
+	}
+
+	if usesFireAndForget {
+		buf.WriteString("\nfunc (c *Client) invokeFireAndForget(ctx context.Context, functionName string, params ...any) {\n")
+		buf.WriteString("workerURI, err := c.workerURI()\n")
+		buf.WriteString("if err == nil {\nerr = rpc.Invoke(ctx, workerURI, functionName, params...)\n}\n")
+		_, _ = fmt.Fprintf(buf, "if err != nil {\nhost.Log(host.LogLevelError, %q, fmt.Sprintf(\"%%s failed for %%s, %%s\", functionName, c.workerName, err))\n}\n}\n", pkg)
+	}
+
+	imports := []string{`"golem-go-project/lib/cfg"`, `"golem-go-project/lib/rpc"`}
+	if usesFireAndForget {
+		imports = []string{`"golem-go-project/lib/cfg"`, `"golem-go-project/lib/host"`, `"golem-go-project/lib/rpc"`}
+	}
+	header := &bytes.Buffer{}
+	header.WriteString("// Code generated by the generateClients magefile command. DO NOT EDIT.\n\n")
+	_, _ = fmt.Fprintf(header, "// Package %s is a typed RPC client for the %s of %s workers\n", pkg, apiInterfaceName, componentName)
+	_, _ = fmt.Fprintf(header, "package %s\n\n", pkg)
+	_, _ = fmt.Fprintf(header, "import (\n\"context\"\n\"fmt\"\n\n%s\n)\n\n", strings.Join(imports, "\n"))
+
+	src, err := format.Source(append(header.Bytes(), buf.Bytes()...))
+	if err != nil {
+		return fmt.Errorf("format failed for %s client, %w", componentName, err)
+	}
+
+	clientDir := filepath.Join(dstClientsDir, pkg)
+	err = os.MkdirAll(clientDir, 0755)
+	if err != nil {
+		return fmt.Errorf("mkdir failed for %s, %w", clientDir, err)
+	}
+
+	clientFile := filepath.Join(clientDir, "client_gen.go")
+	fmt.Printf("Generating client for %s into %s\n", componentName, clientFile)
+	err = os.WriteFile(clientFile, src, 0644)
+	if err != nil {
+		return fmt.Errorf("write file failed for %s, %w", clientFile, err)
+	}
+
+	return nil
+}
+
+// isClientErrorVariant returns whether the type is a variant with clientErrorCases and string payloads
+func isClientErrorVariant(typeDef *wit.TypeDef) bool {
+	if typeDef == nil || typeDef.Kind != wit.TypeDefKindVariant || len(typeDef.Cases) != len(clientErrorCases) {
+		return false
+	}
+	for i, c := range typeDef.Cases {
+		if c.Name != clientErrorCases[i] || c.Type.String() != "string" {
+			return false
+		}
+	}
+	return true
+}
+
+func dashToCamel(s string) string {
+	pascal := dashToPascal(s)
+	if pascal == "" {
+		return pascal
+	}
+	return strings.ToLower(pascal[:1]) + pascal[1:]
+}
@@ -0,0 +1,123 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"os"
+	"sort"
+	"strings"
+)
+
+var componentTargetNamespaces = []struct {
+	Name        string
+	Description string
+	Hook        string
+}{
+	{Name: "Build", Description: "builds %s", Hook: "BuildComponent"},
+	{Name: "Deploy", Description: "adds or updates %s with golem-cli's default profile", Hook: "DeployComponent"},
+	{Name: "Test", Description: "runs the go tests of %s", Hook: "TestComponent"},
+}
+
+// GenerateComponentTargets generates the build, deploy and test namespace targets for all components
+func GenerateComponentTargets() error {
+	fmt.Printf("Generating component targets into %s\n", componentTargetsFile)
+
+	buf := &bytes.Buffer{}
+	buf.WriteString("// Code generated by the generateComponentTargets magefile command. DO NOT EDIT.\n\n")
+	buf.WriteString("package componenttargets\n\n")
+	buf.WriteString("import \"github.com/magefile/mage/mg\"\n")
+
+	componentNames := componentNames()
+	for _, ns := range componentTargetNamespaces {
+		_, _ = fmt.Fprintf(buf, "\n// %s contains the per component %s targets\n", ns.Name, strings.ToLower(ns.Name))
+		_, _ = fmt.Fprintf(buf, "type %s mg.Namespace\n", ns.Name)
+		for _, componentName := range componentNames {
+			targetName := dashToPascal(componentName)
+			_, _ = fmt.Fprintf(buf, "\n// %s %s\n", targetName, fmt.Sprintf(ns.Description, componentName))
+			_, _ = fmt.Fprintf(buf, "func (%s) %s() error {\n", ns.Name, targetName)
+			_, _ = fmt.Fprintf(buf, "\treturn %s(%q)\n", ns.Hook, componentName)
+			buf.WriteString("}\n")
+		}
+	}
+
+	src, err := format.Source(buf.Bytes())
+	if err != nil {
+		return fmt.Errorf("generate component targets: format failed, %w", err)
+	}
+
+	err = os.WriteFile(componentTargetsFile, src, 0644)
+	if err != nil {
+		return fmt.Errorf("generate component targets: write file failed for %s, %w", componentTargetsFile, err)
+	}
+
+	return nil
+}
+
+// selectedComponentNames returns the components selected by name with the COMPONENTS env var and by tag with
+// the TAGS env var (both comma separated), or all the components if none of them are set
+func selectedComponentNames() ([]string, error) {
+	names := splitList(os.Getenv("COMPONENTS"))
+	tags := splitList(os.Getenv("TAGS"))
+
+	allComponentNames := componentNames()
+	if len(names) == 0 && len(tags) == 0 {
+		return allComponentNames, nil
+	}
+
+	knownComponentNames := make(map[string]struct{})
+	for _, componentName := range allComponentNames {
+		knownComponentNames[componentName] = struct{}{}
+	}
+
+	selected := make(map[string]struct{})
+	for _, name := range names {
+		if _, ok := knownComponentNames[name]; !ok {
+			return nil, fmt.Errorf("unknown component in COMPONENTS: %s", name)
+		}
+		selected[name] = struct{}{}
+	}
+
+	for _, tag := range tags {
+		found := false
+		for _, componentName := range allComponentNames {
+			for _, componentTag := range componentTags[componentName] {
+				if componentTag == tag {
+					selected[componentName] = struct{}{}
+					found = true
+				}
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("no components found for tag in TAGS: %s", tag)
+		}
+	}
+
+	var componentNames []string
+	for componentName := range selected {
+		componentNames = append(componentNames, componentName)
+	}
+	sort.Strings(componentNames)
+	return componentNames, nil
+}
+
+func splitList(s string) []string {
+	var values []string
+	for _, value := range strings.Split(s, ",") {
+		value = strings.TrimSpace(value)
+		if value != "" {
+			values = append(values, value)
+		}
+	}
+	return values
+}
+
+func dashToPascal(s string) string {
+	parts := strings.Split(s, "-")
+	for i, part := range parts {
+		if len(part) > 0 {
+			parts[i] = strings.ToUpper(string(part[0])) + part[1:]
+		}
+	}
+	return strings.Join(parts, "")
+}
@@ -0,0 +1,10 @@
+// Package componenttargets contains the per component build, deploy and test targets, which are generated
+// from the component list with the generateComponentTargets magefile command.
+package componenttargets
+
+// The target implementations are set by the magefile, as packages imported by it cannot depend on it.
+var (
+	BuildComponent  func(componentName string) error
+	DeployComponent func(componentName string) error
+	TestComponent   func(componentName string) error
+)
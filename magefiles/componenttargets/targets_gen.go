@@ -0,0 +1,74 @@
+// Code generated by the generateComponentTargets magefile command. DO NOT EDIT.
+
+package componenttargets
+
+import "github.com/magefile/mage/mg"
+
+// Build contains the per component build targets
+type Build mg.Namespace
+
+// ComponentFour builds component-four
+func (Build) ComponentFour() error {
+	return BuildComponent("component-four")
+}
+
+// ComponentOne builds component-one
+func (Build) ComponentOne() error {
+	return BuildComponent("component-one")
+}
+
+// ComponentThree builds component-three
+func (Build) ComponentThree() error {
+	return BuildComponent("component-three")
+}
+
+// ComponentTwo builds component-two
+func (Build) ComponentTwo() error {
+	return BuildComponent("component-two")
+}
+
+// Deploy contains the per component deploy targets
+type Deploy mg.Namespace
+
+// ComponentFour adds or updates component-four with golem-cli's default profile
+func (Deploy) ComponentFour() error {
+	return DeployComponent("component-four")
+}
+
+// ComponentOne adds or updates component-one with golem-cli's default profile
+func (Deploy) ComponentOne() error {
+	return DeployComponent("component-one")
+}
+
+// ComponentThree adds or updates component-three with golem-cli's default profile
+func (Deploy) ComponentThree() error {
+	return DeployComponent("component-three")
+}
+
+// ComponentTwo adds or updates component-two with golem-cli's default profile
+func (Deploy) ComponentTwo() error {
+	return DeployComponent("component-two")
+}
+
+// Test contains the per component test targets
+type Test mg.Namespace
+
+// ComponentFour runs the go tests of component-four
+func (Test) ComponentFour() error {
+	return TestComponent("component-four")
+}
+
+// ComponentOne runs the go tests of component-one
+func (Test) ComponentOne() error {
+	return TestComponent("component-one")
+}
+
+// ComponentThree runs the go tests of component-three
+func (Test) ComponentThree() error {
+	return TestComponent("component-three")
+}
+
+// ComponentTwo runs the go tests of component-two
+func (Test) ComponentTwo() error {
+	return TestComponent("component-two")
+}
@@ -0,0 +1,273 @@
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"golem-go-project/tools/wit"
+)
+
+// witConflictingImports defines the imported interfaces which cannot be used together in a binding world,
+// as their generated bindings collide, see componentBindingWorlds
+var witConflictingImports = [][2]string{
+	{"wasi:keyvalue/eventual", "wasi:blobstore/blobstore"},
+	{"wasi:keyvalue/eventual-batch", "wasi:blobstore/blobstore"},
+}
+
+// wasip2ImportsWorld is the versionless name of the world with the WASI imports of the tinygo runtime, which has to be
+// included by components built with buildTargetWASIP2, as they are not adapted from preview1
+var wasip2ImportsWorld = "wasi:cli/imports"
+
+// rpcImportName is the versionless name of the interface used by the typed RPC clients for invoking the dependencies
+var rpcImportName = "golem:rpc/types"
+
+// LintWit checks the WIT definitions of all components for naming and import consistency
+func LintWit() error {
+	var issues []string
+	for _, componentName := range componentNames() {
+		componentIssues, err := lintComponentWit(componentName)
+		if err != nil {
+			return fmt.Errorf("lint wit: %w", err)
+		}
+		issues = append(issues, componentIssues...)
+	}
+
+	if len(issues) > 0 {
+		for _, issue := range issues {
+			fmt.Println(issue)
+		}
+		return fmt.Errorf("lint wit: found %d issue(s)", len(issues))
+	}
+
+	fmt.Println("WIT definitions are consistent")
+	return nil
+}
+
+func lintComponentWit(componentName string) ([]string, error) {
+	witFiles, err := filepath.Glob(filepath.Join(componentsDir, componentName, "wit", "*.wit"))
+	if err != nil {
+		return nil, fmt.Errorf("glob failed for %s, %w", componentName, err)
+	}
+
+	var issues []string
+	issuef := func(pos wit.Pos, format string, args ...any) {
+		issues = append(issues, fmt.Sprintf("%s: %s", pos, fmt.Sprintf(format, args...)))
+	}
+
+	if len(witFiles) == 0 {
+		issues = append(issues, fmt.Sprintf("%s: missing WIT definition", filepath.Join(componentsDir, componentName, "wit")))
+		return issues, nil
+	}
+
+	for _, witFile := range witFiles {
+		file, err := wit.ParseFile(witFile)
+		if err != nil {
+			issues = append(issues, err.Error())
+			continue
+		}
+
+		expectedPackage := fmt.Sprintf("%s:%s", packageOrg, componentName)
+		if file.Package == nil {
+			issuef(file.Pos, "missing package, should be %s", expectedPackage)
+		} else if actualPackage := file.Package.Namespace + ":" + file.Package.Name; actualPackage != expectedPackage {
+			issuef(file.Package.Pos, "package name should be %s, got %s", expectedPackage, actualPackage)
+		}
+
+		bindings := componentBindings(componentName)
+		bindingWorldNames := make(map[string]bool)
+		for _, bindingWorld := range bindings {
+			bindingWorldNames[bindingWorld.World] = true
+		}
+
+		world := file.World(componentName)
+		for _, w := range file.Worlds {
+			if w.Name != componentName && !bindingWorldNames[w.Name] {
+				issuef(w.Pos, "world name should be %s, got %s", componentName, w.Name)
+			}
+		}
+		if world == nil && len(file.Worlds) == 1 {
+			world = file.Worlds[0]
+		}
+		if world == nil {
+			issuef(file.Pos, "missing world: %s", componentName)
+			continue
+		}
+
+		imports, exports := flattenWorld(file, world)
+
+		apiInterface := componentName + "-api"
+		exportsAPI := false
+		for _, export := range exports {
+			if export.Name == apiInterface {
+				exportsAPI = true
+			} else {
+				issuef(export.Pos, "exported interface should be %s, got %s", apiInterface, export.Name)
+			}
+		}
+		if !exportsAPI {
+			issuef(world.Pos, "world %s should export %s", componentName, apiInterface)
+		}
+
+		issues = append(issues, lintWitImports(componentName, world.Pos, imports)...)
+
+		if componentBuildTarget(componentName) == buildTargetWASIP2 && !includesWorld(file, world, wasip2ImportsWorld) {
+			issuef(world.Pos, "world %s should include %s, as it is built with the %s target", componentName, wasip2ImportsWorld, buildTargetWASIP2)
+		}
+
+		for _, bindingWorld := range bindings {
+			w := world
+			if bindingWorld.World != world.Name {
+				w = file.World(bindingWorld.World)
+			}
+			if w == nil {
+				issuef(file.Pos, "missing binding world: %s", bindingWorld.World)
+				continue
+			}
+			bindingImports, _ := flattenWorld(file, w)
+			issues = append(issues, lintWitConflicts(bindingImports)...)
+		}
+	}
+
+	return issues, nil
+}
+
+// flattenWorld returns the imports and exports of the world, including the ones of the worlds included from the same file
+func flattenWorld(file *wit.File, world *wit.World) (imports, exports []*wit.WorldItem) {
+	visited := make(map[string]bool)
+	var visit func(world *wit.World)
+	visit = func(world *wit.World) {
+		if visited[world.Name] {
+			return
+		}
+		visited[world.Name] = true
+
+		imports = append(imports, world.Imports...)
+		exports = append(exports, world.Exports...)
+		for _, include := range world.Includes {
+			if include.Path.Package != nil {
+				continue
+			}
+			if included := file.World(include.Path.Interface); included != nil {
+				visit(included)
+			}
+		}
+	}
+	visit(world)
+	return imports, exports
+}
+
+// includesWorld reports whether the world includes the versionless external world name, directly or through the
+// worlds included from the same file
+func includesWorld(file *wit.File, world *wit.World, name string) bool {
+	visited := make(map[string]bool)
+	var visit func(world *wit.World) bool
+	visit = func(world *wit.World) bool {
+		if visited[world.Name] {
+			return false
+		}
+		visited[world.Name] = true
+
+		for _, include := range world.Includes {
+			if include.Path.Package == nil {
+				if included := file.World(include.Path.Interface); included != nil && visit(included) {
+					return true
+				}
+				continue
+			}
+			if fmt.Sprintf("%s:%s/%s", include.Path.Package.Namespace, include.Path.Package.Name, include.Path.Interface) == name {
+				return true
+			}
+		}
+		return false
+	}
+	return visit(world)
+}
+
+func lintWitImports(componentName string, worldPos wit.Pos, worldImports []*wit.WorldItem) []string {
+	var issues []string
+	issuef := func(pos wit.Pos, format string, args ...any) {
+		issues = append(issues, fmt.Sprintf("%s: %s", pos, fmt.Sprintf(format, args...)))
+	}
+
+	expectedStubImports := make(map[string]string)
+	for _, dependency := range componentDeps[componentName] {
+		expectedStubImports[stubImportName(dependency)] = dependency
+	}
+
+	imports := make(map[string]*wit.WorldItem)
+	for _, imp := range worldImports {
+		name := witImportName(imp)
+
+		if prev, ok := imports[name]; ok {
+			if prev.Name == imp.Name {
+				issuef(imp.Pos, "duplicate import %s, already imported at line %d", imp.Name, prev.Pos.Line)
+			} else {
+				issuef(imp.Pos, "conflicting import %s, %s is imported at line %d", imp.Name, prev.Name, prev.Pos.Line)
+			}
+			continue
+		}
+		imports[name] = imp
+
+		if imp.Path != nil && imp.Path.Package != nil &&
+			imp.Path.Package.Namespace == packageOrg && strings.HasSuffix(imp.Path.Package.Name, "-stub") {
+			if _, ok := expectedStubImports[name]; !ok {
+				issuef(imp.Pos, "stub import %s is not a configured dependency of %s in componentDeps", imp.Name, componentName)
+			}
+		}
+	}
+
+	var missingStubImports []string
+	for stubImport, dependency := range expectedStubImports {
+		if _, ok := imports[stubImport]; !ok {
+			missingStubImports = append(missingStubImports, fmt.Sprintf("%s (for %s)", stubImport, dependency))
+		}
+	}
+	sort.Strings(missingStubImports)
+	for _, missing := range missingStubImports {
+		issuef(worldPos, "missing stub import for configured dependency: %s", missing)
+	}
+
+	if _, ok := imports[rpcImportName]; !ok && len(expectedStubImports) > 0 {
+		issuef(worldPos, "missing import %s, required by the RPC clients of %s's dependencies", rpcImportName, componentName)
+	}
+
+	return issues
+}
+
+// lintWitConflicts checks the imports of a binding world for interfaces whose generated bindings collide
+func lintWitConflicts(worldImports []*wit.WorldItem) []string {
+	imports := make(map[string]*wit.WorldItem)
+	for _, imp := range worldImports {
+		name := witImportName(imp)
+		if _, ok := imports[name]; !ok {
+			imports[name] = imp
+		}
+	}
+
+	var issues []string
+	for _, conflict := range witConflictingImports {
+		a, aOk := imports[conflict[0]]
+		b, bOk := imports[conflict[1]]
+		if aOk && bOk {
+			issues = append(issues, fmt.Sprintf(
+				"%s: import %s conflicts with %s imported at line %d, move one of them into an extra binding world",
+				a.Pos, a.Name, b.Name, b.Pos.Line,
+			))
+		}
+	}
+	return issues
+}
+
+// witImportName returns the versionless name of an imported interface, or the plain name of other imports
+func witImportName(imp *wit.WorldItem) string {
+	if imp.Path != nil && imp.Path.Package != nil {
+		return fmt.Sprintf("%s:%s/%s", imp.Path.Package.Namespace, imp.Path.Package.Name, imp.Path.Interface)
+	}
+	return imp.Name
+}
+
+func stubImportName(componentName string) string {
+	return fmt.Sprintf("%s:%s-stub/stub-%s", packageOrg, componentName, componentName)
+}
@@ -11,6 +11,9 @@ import (
 
 	"github.com/magefile/mage/sh"
 	"github.com/magefile/mage/target"
+
+	//mage:import
+	"golem-go-project/magefiles/componenttargets"
 )
 
 // componentDeps defines the Worker to Worker RPC dependencies
@@ -24,10 +27,68 @@ var targetDir = "target"
 var componentsDir = "components"
 var libDir = "lib"
 var wasiSnapshotPreview1Adapter = "adapters/tier1/wasi_snapshot_preview1.wasm"
+var componentTargetsFile = "magefiles/componenttargets/targets_gen.go"
+
+// componentTags defines tags per component, which can be used for selecting groups of components with the TAGS env var
+var componentTags = map[string][]string{
+	"component-one":   {"rpc-client"},
+	"component-two":   {"rpc-client", "rpc-server"},
+	"component-three": {"rpc-server"},
+	"component-four":  {"keyvalue"},
+}
+
+func init() {
+	componenttargets.BuildComponent = BuildComponent
+	componenttargets.DeployComponent = DeployComponent
+	componenttargets.TestComponent = TestComponent
+}
+
+// buildTarget selects the pipeline used for building a component
+type buildTarget string
+
+const (
+	// buildTargetWASI builds a preview1 module with tinygo, then embeds the WIT and adapts it into a component
+	buildTargetWASI buildTarget = "wasi"
+	// buildTargetWASIP2 builds a preview2 component directly with tinygo, without embedding and adapting
+	buildTargetWASIP2 buildTarget = "wasip2"
+)
+
+// componentBuildTargets defines the build target per component, components not listed here use buildTargetWASI
+var componentBuildTargets = map[string]buildTarget{
+	"component-two": buildTargetWASIP2,
+}
+
+// bindingWorld is a world of a component's WIT, which is generated into its own go binding package
+type bindingWorld struct {
+	// World is the name of the world in the component's WIT
+	World string
+	// Package is the name of the generated go package, placed into binding/<Package>
+	Package string
+}
+
+// componentBindingWorlds defines extra binding worlds per component. Interfaces whose generated go bindings collide
+// (e.g. wasi:keyvalue and wasi:blobstore) can be imported together by moving one of them into an extra world, which
+// is then generated into its own package. For these components the main binding package is generated from the
+// <component-name>-binding world, and the <component-name> world is expected to include all the binding worlds.
+var componentBindingWorlds = map[string][]bindingWorld{
+	"component-four": {{World: "component-four-keyvalue", Package: "keyvalue"}},
+}
 
-// Build alias for BuildAllComponents
+// Build builds the components selected with the COMPONENTS and TAGS env vars, or all components if none are set
 func Build() error {
-	return BuildAllComponents()
+	componentNames, err := selectedComponentNames()
+	if err != nil {
+		return fmt.Errorf("build: %w", err)
+	}
+
+	for _, componentName := range componentNames {
+		err := BuildComponent(componentName)
+		if err != nil {
+			return fmt.Errorf("build: build component failed for %s, %w", componentName, err)
+		}
+	}
+
+	return nil
 }
 
 // BuildAllComponents builds all components
@@ -42,7 +103,8 @@ func BuildAllComponents() error {
 	return nil
 }
 
-// UpdateRpcStubs builds rpc stub components and adds them as dependency
+// UpdateRpcStubs builds rpc stub components and adds them as dependency, then regenerates the typed RPC clients and
+// the cfg component dependencies
 func UpdateRpcStubs() error {
 	for _, componentName := range stubComponentNames() {
 		err := BuildStubComponent(componentName)
@@ -60,7 +122,17 @@ func UpdateRpcStubs() error {
 		}
 	}
 
-	return nil
+	return UpdateRpcClients()
+}
+
+// UpdateRpcClients regenerates the typed RPC clients and the cfg component dependencies from componentDeps
+func UpdateRpcClients() error {
+	err := GenerateClients()
+	if err != nil {
+		return err
+	}
+
+	return GenerateCfgComponents()
 }
 
 // BuildStubComponent builds RPC stub for component
@@ -71,6 +143,11 @@ func BuildStubComponent(componentName string) error {
 	destWasm := filepath.Join(stubTargetDir, "stub.wasm")
 	destWitDir := filepath.Join(stubTargetDir, "wit")
 
+	err := SyncComponentWitDeps(componentName)
+	if err != nil {
+		return err
+	}
+
 	return opRun(op{
 		RunMessage:  fmt.Sprintf("Building stub component for %s", componentName),
 		SkipMessage: "stub component build",
@@ -176,7 +253,6 @@ func StubCompose(componentName, componentWasm, targetWasm string) error {
 func BuildComponent(componentName string) error {
 	componentDir := filepath.Join(componentsDir, componentName)
 	witDir := filepath.Join(componentDir, "wit")
-	bindingDir := filepath.Join(componentDir, "binding")
 	buildTargetDir := filepath.Join(targetDir, "build", componentName)
 	componentsTargetDir := filepath.Join(targetDir, "components")
 	moduleWasm := filepath.Join(buildTargetDir, "module.wasm")
@@ -184,30 +260,71 @@ func BuildComponent(componentName string) error {
 	componentWasm := filepath.Join(buildTargetDir, "component.wasm")
 	composedComponentWasm := filepath.Join(componentsTargetDir, fmt.Sprintf("%s.wasm", componentName))
 
-	return serialRun(
+	steps := []func() error{
+		func() error { return SyncComponentWitDeps(componentName) },
 		func() error { return os.MkdirAll(buildTargetDir, 0755) },
 		func() error { return os.MkdirAll(componentsTargetDir, 0755) },
-		func() error { return GenerateBinding(witDir, bindingDir) },
-		func() error { return TinyGoBuildComponentBinary(componentDir, moduleWasm) },
-		func() error { return WASMToolsComponentEmbed(witDir, moduleWasm, embedWasm) },
-		func() error { return WASMToolsComponentNew(embedWasm, componentWasm) },
+		func() error { return GenerateBinding(componentName) },
+	}
+
+	switch target := componentBuildTarget(componentName); target {
+	case buildTargetWASI:
+		steps = append(
+			steps,
+			func() error { return TinyGoBuildComponentBinary(componentDir, moduleWasm) },
+			func() error { return WASMToolsComponentEmbed(witDir, componentName, moduleWasm, embedWasm) },
+			func() error { return WASMToolsComponentNew(embedWasm, componentWasm) },
+		)
+	case buildTargetWASIP2:
+		steps = append(
+			steps,
+			func() error { return TinyGoBuildComponentWASIP2(componentDir, witDir, componentName, componentWasm) },
+		)
+	default:
+		return fmt.Errorf("build component: unknown build target for %s: %s", componentName, target)
+	}
+
+	steps = append(
+		steps,
 		func() error {
 			return StubCompose(componentName, componentWasm, composedComponentWasm)
 		},
 	)
+
+	return serialRun(steps...)
 }
 
-// GenerateBinding generates go binding from WIT
-func GenerateBinding(witDir, bindingDir string) error {
-	return opRun(op{
-		RunMessage:  fmt.Sprintf("Generating bindings from %s into %s", witDir, bindingDir),
-		SkipMessage: "binding generation",
-		Targets:     []string{bindingDir},
-		SourcePaths: []string{witDir},
-		Run: func() error {
-			return sh.RunV("wit-bindgen", "tiny-go", "--rename-package", "binding", "--out-dir", bindingDir, witDir)
-		},
-	})
+// GenerateBinding generates go bindings from the component's WIT, including the extra binding worlds
+func GenerateBinding(componentName string) error {
+	witDir := filepath.Join(componentsDir, componentName, "wit")
+
+	for _, bindingWorld := range componentBindings(componentName) {
+		bindingDir := bindingWorld.dir(filepath.Join(componentsDir, componentName))
+		err := opRun(op{
+			RunMessage:  fmt.Sprintf("Generating bindings from %s (%s) into %s", witDir, bindingWorld.World, bindingDir),
+			SkipMessage: "binding generation",
+			Targets:     []string{bindingDir},
+			SourcePaths: []string{witDir},
+			Run: func() error {
+				return generateBinding(witDir, bindingWorld, bindingDir)
+			},
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func generateBinding(witDir string, bindingWorld bindingWorld, bindingDir string) error {
+	return sh.RunV(
+		"wit-bindgen", "tiny-go",
+		"--world", bindingWorld.World,
+		"--rename-package", bindingWorld.Package,
+		"--out-dir", bindingDir,
+		witDir,
+	)
 }
 
 // TinyGoBuildComponentBinary build wasm component binary with tiny go
@@ -221,22 +338,42 @@ func TinyGoBuildComponentBinary(componentDir, moduleWasm string) error {
 			return sh.RunV(
 				"tinygo", "build", "-target=wasi", "-tags=purego",
 				"-o", moduleWasm,
-				filepath.Join(componentDir, "main.go"),
+				"./"+filepath.ToSlash(componentDir),
+			)
+		},
+	})
+}
+
+// TinyGoBuildComponentWASIP2 builds wasip2 component with tiny go, without using the preview1 adapter
+func TinyGoBuildComponentWASIP2(componentDir, witDir, worldName, componentWasm string) error {
+	return opRun(op{
+		RunMessage:  fmt.Sprintf("Building wasip2 component with tiny go: %s", componentWasm),
+		SkipMessage: "tinygo wasip2 component build",
+		Targets:     []string{componentWasm},
+		SourcePaths: []string{componentsDir, libDir},
+		Run: func() error {
+			return sh.RunV(
+				"tinygo", "build", "-target=wasip2", "-tags=purego",
+				"--wit-package", witDir,
+				"--wit-world", worldName,
+				"-o", componentWasm,
+				"./"+filepath.ToSlash(componentDir),
 			)
 		},
 	})
 }
 
 // WASMToolsComponentEmbed embeds type info into wasm component with wasm-tools
-func WASMToolsComponentEmbed(witDir, moduleWasm, embedWasm string) error {
+func WASMToolsComponentEmbed(witDir, worldName, moduleWasm, embedWasm string) error {
 	return opRun(op{
-		RunMessage:  fmt.Sprintf("Embedding component type info (%s, %s) -> %s", moduleWasm, witDir, embedWasm),
+		RunMessage:  fmt.Sprintf("Embedding component type info (%s, %s, %s) -> %s", moduleWasm, witDir, worldName, embedWasm),
 		SkipMessage: "wasm-tools component embed",
 		Targets:     []string{embedWasm},
 		SourcePaths: []string{witDir, moduleWasm},
 		Run: func() error {
 			return sh.RunV(
 				"wasm-tools", "component", "embed",
+				"--world", worldName,
 				witDir, moduleWasm,
 				"--output", embedWasm,
 			)
@@ -269,7 +406,11 @@ func GenerateNewComponent(componentName string) error {
 		return fmt.Errorf("generate new component failed for %s, %w", componentName, err)
 	}
 
-	return nil
+	return serialRun(
+		func() error { return SyncComponentWitDeps(componentName) },
+		GenerateComponentTargets,
+		GenerateCfgComponents,
+	)
 }
 
 // Clean cleans the projects
@@ -292,24 +433,63 @@ func Clean() error {
 	return nil
 }
 
-// Deploy adds or updates all the components with golem-cli's default profile
+// Deploy adds or updates the components selected with the COMPONENTS and TAGS env vars with golem-cli's default profile
 func Deploy() error {
-	componentsTargetDir := filepath.Join(targetDir, "components")
-	for _, componentName := range componentNames() {
-		wasm := filepath.Join(componentsTargetDir, fmt.Sprintf("%s.wasm", componentName))
-		err := sh.RunV(
-			"golem-cli", "component", "add",
-			"--non-interactive",
-			"--component-name", componentName,
-			wasm,
-		)
+	componentNames, err := selectedComponentNames()
+	if err != nil {
+		return fmt.Errorf("deploy: %w", err)
+	}
+
+	for _, componentName := range componentNames {
+		err := DeployComponent(componentName)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// DeployComponent adds or updates component by name with golem-cli's default profile
+func DeployComponent(componentName string) error {
+	wasm := filepath.Join(targetDir, "components", fmt.Sprintf("%s.wasm", componentName))
+	err := sh.RunV(
+		"golem-cli", "component", "add",
+		"--non-interactive",
+		"--component-name", componentName,
+		wasm,
+	)
+	if err != nil {
+		return fmt.Errorf("deploy: failed for %s, %w", componentName, err)
+	}
+	return nil
+}
+
+// Test tests the components selected with the COMPONENTS and TAGS env vars, or all components if none are set
+func Test() error {
+	componentNames, err := selectedComponentNames()
+	if err != nil {
+		return fmt.Errorf("test: %w", err)
+	}
+
+	for _, componentName := range componentNames {
+		err := TestComponent(componentName)
 		if err != nil {
-			return fmt.Errorf("deploy: failed for %s, %w", componentName, err)
+			return err
 		}
 	}
 	return nil
 }
 
+// TestComponent runs the go tests of component by name on the host, using the hosttest build tag
+func TestComponent(componentName string) error {
+	err := sh.RunV("go", "test", "-tags", "hosttest", "./"+filepath.ToSlash(filepath.Join(componentsDir, componentName)), "-v")
+	if err != nil {
+		return fmt.Errorf("test component failed for %s: %w", componentName, err)
+	}
+
+	return nil
+}
+
 // TestIntegration tests the deployed components
 func TestIntegration() error {
 	err := sh.RunV("go", "test", "./integration", "-v")
@@ -332,6 +512,32 @@ func componentNames() []string {
 	return componentNames
 }
 
+func componentBuildTarget(componentName string) buildTarget {
+	target, ok := componentBuildTargets[componentName]
+	if !ok {
+		return buildTargetWASI
+	}
+	return target
+}
+
+// componentBindings returns the binding worlds of the component, starting with the main binding package
+func componentBindings(componentName string) []bindingWorld {
+	extraBindingWorlds := componentBindingWorlds[componentName]
+	mainWorld := componentName
+	if len(extraBindingWorlds) > 0 {
+		mainWorld = componentName + "-binding"
+	}
+	return append([]bindingWorld{{World: mainWorld, Package: "binding"}}, extraBindingWorlds...)
+}
+
+// dir returns the directory of the generated binding package in the component dir
+func (w bindingWorld) dir(componentDir string) string {
+	if w.Package == "binding" {
+		return filepath.Join(componentDir, "binding")
+	}
+	return filepath.Join(componentDir, "binding", w.Package)
+}
+
 func stubComponentNames() []string {
 	componentNamesSet := make(map[string]struct{})
 	for _, deps := range componentDeps {
@@ -0,0 +1,161 @@
+package main
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"golem-go-project/tools/wit"
+	"golem-go-project/tools/witprune"
+)
+
+var goModule = "golem-go-project"
+
+// CheckUnusedWitImports reports the world imports of the selected components, which are not used by their go code
+func CheckUnusedWitImports() error {
+	return pruneWitImports(false)
+}
+
+// PruneWitImports removes the world imports of the selected components, which are not used by their go code
+func PruneWitImports() error {
+	return pruneWitImports(true)
+}
+
+func pruneWitImports(apply bool) error {
+	componentNames, err := selectedComponentNames()
+	if err != nil {
+		return fmt.Errorf("prune wit imports: %w", err)
+	}
+
+	libDirs, err := libPackageDirs()
+	if err != nil {
+		return fmt.Errorf("prune wit imports: %w", err)
+	}
+	golemGoIdents, err := witprune.GolemGoBindingIdents(libDirs...)
+	if err != nil {
+		return fmt.Errorf("prune wit imports: %w", err)
+	}
+
+	unusedCount := 0
+	for _, componentName := range componentNames {
+		err := SyncComponentWitDeps(componentName)
+		if err != nil {
+			return fmt.Errorf("prune wit imports: %w", err)
+		}
+
+		count, err := pruneComponentWitImports(componentName, golemGoIdents, apply)
+		if err != nil {
+			return fmt.Errorf("prune wit imports: %w", err)
+		}
+		unusedCount += count
+	}
+
+	switch {
+	case unusedCount == 0:
+		fmt.Println("No unused WIT imports found")
+	case apply:
+		fmt.Printf("Removed %d unused WIT import(s), run build to regenerate the bindings\n", unusedCount)
+	default:
+		fmt.Printf("Found %d unused WIT import(s), run pruneWitImports to remove them\n", unusedCount)
+	}
+
+	return nil
+}
+
+// pruneComponentWitImports reports or removes the unused imports of the component, the interfaces of golemGoIdents
+// are always used, as their C functions are called by golem-go's binding package
+func pruneComponentWitImports(componentName string, golemGoIdents map[string]bool, apply bool) (int, error) {
+	componentDir := filepath.Join(componentsDir, componentName)
+	witDir := filepath.Join(componentDir, "wit")
+
+	deps, err := witprune.LoadDeps(filepath.Join(witDir, "deps"))
+	if err != nil {
+		return 0, err
+	}
+
+	// the stub imports are kept, as they are required for composing the dependencies
+	keep := make(map[string]bool)
+	for _, dependency := range componentDeps[componentName] {
+		keep[stubImportName(dependency)] = true
+	}
+
+	witFiles, err := filepath.Glob(filepath.Join(witDir, "*.wit"))
+	if err != nil {
+		return 0, fmt.Errorf("glob failed for %s, %w", witDir, err)
+	}
+
+	unusedCount := 0
+	for _, witFile := range witFiles {
+		file, err := wit.ParseFile(witFile)
+		if err != nil {
+			return 0, err
+		}
+
+		var unused []*wit.WorldItem
+		for _, bindingWorld := range componentBindings(componentName) {
+			world := file.World(bindingWorld.World)
+			if world == nil {
+				continue
+			}
+
+			bindingImportPath := goModule + "/" + filepath.ToSlash(bindingWorld.dir(componentDir))
+			used, err := witprune.UsedIdents(componentDir, bindingImportPath)
+			if err != nil {
+				return 0, err
+			}
+			for ident := range golemGoIdents {
+				used[ident] = true
+			}
+
+			worldUnused, err := witprune.UnusedImports(world, deps, used, keep)
+			if err != nil {
+				return 0, err
+			}
+			unused = append(unused, worldUnused...)
+		}
+
+		for _, imp := range unused {
+			fmt.Printf("%s: unused import %s\n", imp.Pos, imp.Name)
+		}
+		unusedCount += len(unused)
+
+		if apply && len(unused) > 0 {
+			src, err := os.ReadFile(witFile)
+			if err != nil {
+				return 0, fmt.Errorf("read failed for %s, %w", witFile, err)
+			}
+			err = os.WriteFile(witFile, witprune.Prune(src, unused), 0644)
+			if err != nil {
+				return 0, fmt.Errorf("write failed for %s, %w", witFile, err)
+			}
+		}
+	}
+
+	return unusedCount, nil
+}
+
+// libPackageDirs returns the dirs of the lib folder containing go files
+func libPackageDirs() ([]string, error) {
+	var dirs []string
+	err := filepath.WalkDir(libDir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.IsDir() {
+			return nil
+		}
+		goFiles, err := filepath.Glob(filepath.Join(path, "*.go"))
+		if err != nil {
+			return err
+		}
+		if len(goFiles) > 0 {
+			dirs = append(dirs, path)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("walk failed for %s, %w", libDir, err)
+	}
+	return dirs, nil
+}
@@ -0,0 +1,301 @@
+package main
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+var witDepsDir = "wit-deps"
+var witDepsLockFile = filepath.Join(witDepsDir, "deps.lock")
+
+// SyncWitDeps verifies the shared WIT dependency store, then materializes it into all components' wit/deps
+func SyncWitDeps() error {
+	deps, err := verifiedWitDeps()
+	if err != nil {
+		return fmt.Errorf("sync wit deps: %w", err)
+	}
+
+	for _, componentName := range componentNames() {
+		err := syncComponentWitDeps(componentName, deps)
+		if err != nil {
+			return fmt.Errorf("sync wit deps: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// SyncComponentWitDeps verifies the shared WIT dependency store, then materializes it into the component's wit/deps
+func SyncComponentWitDeps(componentName string) error {
+	deps, err := verifiedWitDeps()
+	if err != nil {
+		return fmt.Errorf("sync component wit deps: %w", err)
+	}
+
+	err = syncComponentWitDeps(componentName, deps)
+	if err != nil {
+		return fmt.Errorf("sync component wit deps: %w", err)
+	}
+
+	return nil
+}
+
+// VerifyWitDeps verifies the shared WIT dependency store and all components' wit/deps without changing them
+func VerifyWitDeps() error {
+	deps, err := verifiedWitDeps()
+	if err != nil {
+		return fmt.Errorf("verify wit deps: %w", err)
+	}
+
+	drifts, err := componentWitDepsDrifts(deps)
+	if err != nil {
+		return fmt.Errorf("verify wit deps: %w", err)
+	}
+
+	if len(drifts) > 0 {
+		for _, drift := range drifts {
+			fmt.Println(drift)
+		}
+		return fmt.Errorf("verify wit deps: found %d drifted dependencies, use syncWitDeps to update them", len(drifts))
+	}
+
+	fmt.Println("WIT dependencies are in sync")
+	return nil
+}
+
+// componentWitDepsDrifts returns the components' wit/deps dirs, which are missing or differ from the verified store
+func componentWitDepsDrifts(deps map[string]string) ([]string, error) {
+	var drifts []string
+	for _, componentName := range componentNames() {
+		for _, dep := range sortedKeys(deps) {
+			depDir := filepath.Join(componentsDir, componentName, "wit", "deps", dep)
+			hash, err := hashDir(depDir)
+			if err != nil {
+				return nil, err
+			}
+			switch hash {
+			case deps[dep]:
+			case "":
+				drifts = append(drifts, fmt.Sprintf("%s is missing", depDir))
+			default:
+				drifts = append(drifts, fmt.Sprintf("%s differs from %s", depDir, filepath.Join(witDepsDir, dep)))
+			}
+		}
+	}
+	return drifts, nil
+}
+
+// UpdateWitDepsLock updates the content hashes of the shared WIT dependency store's lock file
+func UpdateWitDepsLock() error {
+	deps, err := hashWitDeps()
+	if err != nil {
+		return fmt.Errorf("update wit deps lock: %w", err)
+	}
+
+	var lock strings.Builder
+	lock.WriteString("# Content hashes of the shared WIT dependencies, use the updateWitDepsLock magefile command to update\n")
+	for _, dep := range sortedKeys(deps) {
+		_, _ = fmt.Fprintf(&lock, "\n[%s]\nsha256 = %q\n", dep, deps[dep])
+	}
+
+	fmt.Printf("Updating %s\n", witDepsLockFile)
+	err = os.WriteFile(witDepsLockFile, []byte(lock.String()), 0644)
+	if err != nil {
+		return fmt.Errorf("update wit deps lock: write failed for %s, %w", witDepsLockFile, err)
+	}
+
+	return nil
+}
+
+func syncComponentWitDeps(componentName string, deps map[string]string) error {
+	for _, dep := range sortedKeys(deps) {
+		srcDir := filepath.Join(witDepsDir, dep)
+		dstDir := filepath.Join(componentsDir, componentName, "wit", "deps", dep)
+
+		hash, err := hashDir(dstDir)
+		if err != nil {
+			return err
+		}
+		switch hash {
+		case deps[dep]:
+			continue
+		case "":
+			fmt.Printf("Materializing %s into %s\n", srcDir, dstDir)
+		default:
+			fmt.Printf("Drift detected, %s differs from %s, overwriting it\n", dstDir, srcDir)
+		}
+
+		err = os.RemoveAll(dstDir)
+		if err != nil {
+			return fmt.Errorf("remove all failed for %s, %w", dstDir, err)
+		}
+		err = copyDir(srcDir, dstDir)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// verifiedWitDeps returns the content hashes of the shared WIT dependency store, after checking them against the lock file
+func verifiedWitDeps() (map[string]string, error) {
+	lockedDeps, err := readWitDepsLock()
+	if err != nil {
+		return nil, err
+	}
+
+	deps, err := hashWitDeps()
+	if err != nil {
+		return nil, err
+	}
+
+	var drifts []string
+	for _, dep := range sortedKeys(deps) {
+		lockedHash, ok := lockedDeps[dep]
+		if !ok {
+			drifts = append(drifts, fmt.Sprintf("%s is not locked", dep))
+		} else if lockedHash != deps[dep] {
+			drifts = append(drifts, fmt.Sprintf("%s does not match the locked hash", dep))
+		}
+	}
+	for _, dep := range sortedKeys(lockedDeps) {
+		if _, ok := deps[dep]; !ok {
+			drifts = append(drifts, fmt.Sprintf("%s is locked but missing", dep))
+		}
+	}
+	if len(drifts) > 0 {
+		return nil, fmt.Errorf(
+			"%s is out of sync with %s (%s), use updateWitDepsLock after changing the store",
+			witDepsDir, witDepsLockFile, strings.Join(drifts, ", "),
+		)
+	}
+
+	return deps, nil
+}
+
+func hashWitDeps() (map[string]string, error) {
+	entries, err := os.ReadDir(witDepsDir)
+	if err != nil {
+		return nil, fmt.Errorf("read dir failed for %s, %w", witDepsDir, err)
+	}
+
+	deps := make(map[string]string)
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		hash, err := hashDir(filepath.Join(witDepsDir, entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+		deps[entry.Name()] = hash
+	}
+
+	return deps, nil
+}
+
+func readWitDepsLock() (map[string]string, error) {
+	f, err := os.Open(witDepsLockFile)
+	if err != nil {
+		return nil, fmt.Errorf("open failed for %s, %w", witDepsLockFile, err)
+	}
+	defer func() { _ = f.Close() }()
+
+	deps := make(map[string]string)
+	var dep string
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		switch {
+		case line == "" || strings.HasPrefix(line, "#"):
+		case strings.HasPrefix(line, "[") && strings.HasSuffix(line, "]"):
+			dep = strings.TrimSuffix(strings.TrimPrefix(line, "["), "]")
+		case strings.HasPrefix(line, "sha256") && dep != "":
+			parts := strings.SplitN(line, "=", 2)
+			if len(parts) != 2 {
+				return nil, fmt.Errorf("invalid line in %s: %s", witDepsLockFile, line)
+			}
+			deps[dep] = strings.Trim(strings.TrimSpace(parts[1]), `"`)
+		default:
+			return nil, fmt.Errorf("invalid line in %s: %s", witDepsLockFile, line)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("read failed for %s, %w", witDepsLockFile, err)
+	}
+
+	return deps, nil
+}
+
+// hashDir returns the sha256 hash of the relative paths and contents of all files in the directory,
+// or an empty string if the directory does not exist
+func hashDir(dir string) (string, error) {
+	if _, err := os.Stat(dir); os.IsNotExist(err) {
+		return "", nil
+	}
+
+	var paths []string
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.IsDir() {
+			paths = append(paths, path)
+		}
+		return nil
+	})
+	if err != nil {
+		return "", fmt.Errorf("hashDir: walk failed for %s, %w", dir, err)
+	}
+	sort.Strings(paths)
+
+	hash := sha256.New()
+	for _, path := range paths {
+		relPath, err := filepath.Rel(dir, path)
+		if err != nil {
+			return "", fmt.Errorf("hashDir: rel failed for %s, %w", path, err)
+		}
+		contents, err := os.ReadFile(path)
+		if err != nil {
+			return "", fmt.Errorf("hashDir: read failed for %s, %w", path, err)
+		}
+		_, _ = fmt.Fprintf(hash, "%s\x00%d\x00", filepath.ToSlash(relPath), len(contents))
+		_, _ = hash.Write(contents)
+	}
+
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+func copyDir(srcDir, dstDir string) error {
+	return filepath.WalkDir(srcDir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		relPath, err := filepath.Rel(srcDir, path)
+		if err != nil {
+			return fmt.Errorf("copyDir: rel failed for %s, %w", path, err)
+		}
+		dstPath := filepath.Join(dstDir, relPath)
+		if d.IsDir() {
+			return os.MkdirAll(dstPath, 0755)
+		}
+		return copyFile(path, dstPath)
+	})
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,61 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/magefile/mage/sh"
+	"github.com/tidwall/gjson"
+
+	"golem-go-project/tools/workerenv"
+)
+
+// WorkerEnv prints the component id env vars needed by the workers of the component for its direct and transitive RPC
+// dependencies, using golem-cli's default profile, in dotenv or args (golem-cli --env) format
+func WorkerEnv(componentName, format string) error {
+	if format != "dotenv" && format != "args" {
+		return fmt.Errorf("worker env: expected dotenv or args format, got %q", format)
+	}
+	known := false
+	for _, name := range componentNames() {
+		known = known || name == componentName
+	}
+	if !known {
+		return fmt.Errorf("worker env: unknown component: %s", componentName)
+	}
+
+	graph := workerenv.Graph(componentDeps)
+	componentURNs := map[string]string{}
+	for _, dependency := range graph.Dependencies(componentName) {
+		urn, err := deployedComponentURN(dependency)
+		if err != nil {
+			return fmt.Errorf("worker env: %w", err)
+		}
+		componentURNs[dependency] = urn
+	}
+
+	env, err := workerenv.Compute(graph, componentURNs, componentName)
+	if err != nil {
+		return err
+	}
+
+	if format == "dotenv" {
+		fmt.Print(env.DotEnv())
+	} else {
+		fmt.Println(strings.Join(env.CLIArgs(), " "))
+	}
+	return nil
+}
+
+// deployedComponentURN returns the URN of the component with golem-cli's default profile
+func deployedComponentURN(componentName string) (string, error) {
+	output, err := sh.Output("golem-cli", "--format", "json", "component", "get", "--component-name", componentName)
+	if err != nil {
+		return "", fmt.Errorf("get component failed for %s, %w\n%s", componentName, err, output)
+	}
+	urn := gjson.Get(output, "componentUrn").String()
+	if urn == "" {
+		return "", fmt.Errorf("missing componentUrn for %s in golem-cli output:\n%s", componentName, output)
+	}
+	return urn, nil
+}
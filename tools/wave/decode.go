@@ -0,0 +1,298 @@
+package wave
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"unicode/utf8"
+)
+
+var resultDecoderType = reflect.TypeOf((*resultDecoder)(nil)).Elem()
+
+// DecodeResults decodes the results of an invocation from the JSON output of golem-cli, which has the results in its
+// value array, into the go values pointed to by the results, the number of results must match
+func DecodeResults(output string, results ...any) error {
+	var parsed struct {
+		Value []any `json:"value"`
+	}
+	err := unmarshalJSON(output, &parsed)
+	if err != nil {
+		return fmt.Errorf("wave: decode results: %w", err)
+	}
+	if parsed.Value == nil {
+		return fmt.Errorf("wave: decode results: missing value in output: %s", output)
+	}
+	if len(parsed.Value) != len(results) {
+		return fmt.Errorf("wave: decode results: expected %d result(s), got %d", len(results), len(parsed.Value))
+	}
+	for i, value := range parsed.Value {
+		err := decodeValue(value, results[i])
+		if err != nil {
+			return fmt.Errorf("wave: decode results: result #%d to %T: %w", i, results[i], err)
+		}
+	}
+	return nil
+}
+
+// DecodeJSON decodes a JSON value, using golem's JSON representation of WIT values, into the go value pointed to by
+// target
+func DecodeJSON(data string, target any) error {
+	var value any
+	err := unmarshalJSON(data, &value)
+	if err == nil {
+		err = decodeValue(value, target)
+	}
+	if err != nil {
+		return fmt.Errorf("wave: decode to %T: %w", target, err)
+	}
+	return nil
+}
+
+func unmarshalJSON(data string, target any) error {
+	decoder := json.NewDecoder(bytes.NewBufferString(data))
+	decoder.UseNumber()
+	return decoder.Decode(target)
+}
+
+func decodeValue(value any, target any) error {
+	rv := reflect.ValueOf(target)
+	if rv.Kind() != reflect.Pointer || rv.IsNil() {
+		return fmt.Errorf("target must be a non-nil pointer")
+	}
+	return decode(value, rv.Elem())
+}
+
+// decodePayload decodes the payload of results, null payloads can be decoded to struct{} and interfaces
+func decodePayload(payload any, target any) error {
+	rv := reflect.ValueOf(target).Elem()
+	if payload == nil && (rv.Kind() == reflect.Interface || rv.Type() == unitType) {
+		rv.Set(reflect.Zero(rv.Type()))
+		return nil
+	}
+	return decodeValue(payload, target)
+}
+
+func decode(value any, rv reflect.Value) error {
+	if rv.Kind() == reflect.Interface {
+		if value == nil {
+			rv.Set(reflect.Zero(rv.Type()))
+		} else {
+			rv.Set(reflect.ValueOf(value))
+		}
+		return nil
+	}
+
+	if rv.Kind() != reflect.Pointer && reflect.PointerTo(rv.Type()).Implements(resultDecoderType) {
+		object, err := expectObject(value, "result")
+		if err != nil {
+			return err
+		}
+		caseName, payload, err := singleCase(object)
+		if err != nil {
+			return err
+		}
+		if caseName != "ok" && caseName != "err" {
+			return fmt.Errorf("expected ok or err result case, got %s", caseName)
+		}
+		return rv.Addr().Interface().(resultDecoder).decodeWaveResult(caseName == "err", payload)
+	}
+
+	switch rv.Type() {
+	case charType:
+		s, err := expectString(value, "char")
+		if err != nil {
+			return err
+		}
+		if utf8.RuneCountInString(s) != 1 {
+			return fmt.Errorf("expected char, got %q", s)
+		}
+		r, _ := utf8.DecodeRuneInString(s)
+		rv.SetInt(int64(r))
+		return nil
+	case tupleType:
+		items, err := expectArray(value, "tuple")
+		if err != nil {
+			return err
+		}
+		rv.Set(reflect.ValueOf(Tuple(items)))
+		return nil
+	case variantType:
+		// cases without payload may be represented by their name only
+		if s, ok := value.(string); ok {
+			rv.Set(reflect.ValueOf(Variant{Case: s}))
+			return nil
+		}
+		object, err := expectObject(value, "variant")
+		if err != nil {
+			return err
+		}
+		caseName, payload, err := singleCase(object)
+		if err != nil {
+			return err
+		}
+		rv.Set(reflect.ValueOf(Variant{Case: caseName, Payload: payload}))
+		return nil
+	}
+
+	switch rv.Kind() {
+	case reflect.Bool:
+		b, ok := value.(bool)
+		if !ok {
+			return fmt.Errorf("expected bool, got %s", describe(value))
+		}
+		rv.SetBool(b)
+	case reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uint:
+		n, err := expectNumber(value)
+		if err != nil {
+			return err
+		}
+		u, err := strconv.ParseUint(n.String(), 10, rv.Type().Bits())
+		if err != nil {
+			return fmt.Errorf("expected %s, got %s", rv.Type(), n)
+		}
+		rv.SetUint(u)
+	case reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64, reflect.Int:
+		n, err := expectNumber(value)
+		if err != nil {
+			return err
+		}
+		i, err := strconv.ParseInt(n.String(), 10, rv.Type().Bits())
+		if err != nil {
+			return fmt.Errorf("expected %s, got %s", rv.Type(), n)
+		}
+		rv.SetInt(i)
+	case reflect.Float32, reflect.Float64:
+		n, err := expectNumber(value)
+		if err != nil {
+			return err
+		}
+		f, err := strconv.ParseFloat(n.String(), rv.Type().Bits())
+		if err != nil {
+			return fmt.Errorf("expected %s, got %s", rv.Type(), n)
+		}
+		rv.SetFloat(f)
+	case reflect.String:
+		s, err := expectString(value, rv.Type().String())
+		if err != nil {
+			return err
+		}
+		rv.SetString(s)
+	case reflect.Slice:
+		items, err := expectArray(value, "list")
+		if err != nil {
+			return err
+		}
+		slice := reflect.MakeSlice(rv.Type(), len(items), len(items))
+		for i, item := range items {
+			err := decode(item, slice.Index(i))
+			if err != nil {
+				return fmt.Errorf("item #%d: %w", i, err)
+			}
+		}
+		rv.Set(slice)
+	case reflect.Array:
+		items, err := expectArray(value, "list")
+		if err != nil {
+			return err
+		}
+		if len(items) != rv.Len() {
+			return fmt.Errorf("expected %d item(s), got %d", rv.Len(), len(items))
+		}
+		for i, item := range items {
+			err := decode(item, rv.Index(i))
+			if err != nil {
+				return fmt.Errorf("item #%d: %w", i, err)
+			}
+		}
+	case reflect.Pointer:
+		if value == nil {
+			rv.Set(reflect.Zero(rv.Type()))
+			return nil
+		}
+		elem := reflect.New(rv.Type().Elem())
+		err := decode(value, elem.Elem())
+		if err != nil {
+			return err
+		}
+		rv.Set(elem)
+	case reflect.Struct:
+		object, err := expectObject(value, "record")
+		if err != nil {
+			return err
+		}
+		for _, field := range recordFields(rv.Type()) {
+			err := decode(object[field.name], rv.Field(field.index))
+			if err != nil {
+				return fmt.Errorf("field %s: %w", field.name, err)
+			}
+		}
+	default:
+		return fmt.Errorf("unsupported type: %s", rv.Type())
+	}
+	return nil
+}
+
+func singleCase(object map[string]any) (string, any, error) {
+	if len(object) != 1 {
+		return "", nil, fmt.Errorf("expected single case, got %d", len(object))
+	}
+	var caseName string
+	var payload any
+	for caseName, payload = range object {
+	}
+	return caseName, payload, nil
+}
+
+func expectObject(value any, kind string) (map[string]any, error) {
+	object, ok := value.(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("expected %s, got %s", kind, describe(value))
+	}
+	return object, nil
+}
+
+func expectArray(value any, kind string) ([]any, error) {
+	items, ok := value.([]any)
+	if !ok {
+		return nil, fmt.Errorf("expected %s, got %s", kind, describe(value))
+	}
+	return items, nil
+}
+
+func expectString(value any, kind string) (string, error) {
+	s, ok := value.(string)
+	if !ok {
+		return "", fmt.Errorf("expected %s, got %s", kind, describe(value))
+	}
+	return s, nil
+}
+
+func expectNumber(value any) (json.Number, error) {
+	n, ok := value.(json.Number)
+	if !ok {
+		return "", fmt.Errorf("expected number, got %s", describe(value))
+	}
+	return n, nil
+}
+
+// describe returns the JSON type of the value for error messages
+func describe(value any) string {
+	switch value.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "bool"
+	case json.Number:
+		return "number"
+	case string:
+		return "string"
+	case []any:
+		return "array"
+	case map[string]any:
+		return "object"
+	default:
+		return fmt.Sprintf("%T", value)
+	}
+}
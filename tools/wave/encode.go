@@ -0,0 +1,258 @@
+package wave
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+var (
+	charType    = reflect.TypeOf(Char(0))
+	enumType    = reflect.TypeOf(Enum(""))
+	flagsType   = reflect.TypeOf(Flags(nil))
+	tupleType   = reflect.TypeOf(Tuple(nil))
+	variantType = reflect.TypeOf(Variant{})
+	unitType    = reflect.TypeOf(struct{}{})
+)
+
+// Encode encodes the go value as a WAVE string
+func Encode(value any) (string, error) {
+	sb := &strings.Builder{}
+	err := encode(sb, reflect.ValueOf(value))
+	if err != nil {
+		return "", fmt.Errorf("wave: encode %T: %w", value, err)
+	}
+	return sb.String(), nil
+}
+
+// EncodeArgs encodes the function arguments, e.g. for the --arg flags of golem-cli
+func EncodeArgs(args ...any) ([]string, error) {
+	encodedArgs := make([]string, len(args))
+	for i, arg := range args {
+		encodedArg, err := Encode(arg)
+		if err != nil {
+			return nil, fmt.Errorf("arg #%d: %w", i, err)
+		}
+		encodedArgs[i] = encodedArg
+	}
+	return encodedArgs, nil
+}
+
+func encode(sb *strings.Builder, rv reflect.Value) error {
+	if !rv.IsValid() {
+		return fmt.Errorf("unsupported nil value")
+	}
+	for rv.Kind() == reflect.Interface {
+		if rv.IsNil() {
+			return fmt.Errorf("unsupported nil value")
+		}
+		rv = rv.Elem()
+	}
+
+	// pointers are options, even if they implement the interfaces through the methods of their element type
+	if rv.Kind() != reflect.Pointer && rv.CanInterface() {
+		switch v := rv.Interface().(type) {
+		case VariantMarshaler:
+			variant, err := v.MarshalWaveVariant()
+			if err != nil {
+				return err
+			}
+			return encodeVariant(sb, variant)
+		case resultMarshaler:
+			isErr, payload := v.marshalWaveResult()
+			if isErr {
+				return encodeCase(sb, "err", reflect.ValueOf(payload))
+			}
+			return encodeCase(sb, "ok", reflect.ValueOf(payload))
+		}
+	}
+
+	switch rv.Type() {
+	case charType:
+		sb.WriteString(quote(string(rune(rv.Int())), '\''))
+		return nil
+	case enumType:
+		return encodeLabel(sb, rv.String())
+	case flagsType:
+		return encodeItems(sb, "{", "}", rv, func(rv reflect.Value) error {
+			return encodeLabel(sb, rv.String())
+		})
+	case tupleType:
+		return encodeItems(sb, "(", ")", rv, func(rv reflect.Value) error {
+			return encode(sb, rv)
+		})
+	case variantType:
+		return encodeVariant(sb, rv.Interface().(Variant))
+	}
+
+	switch rv.Kind() {
+	case reflect.Bool:
+		sb.WriteString(strconv.FormatBool(rv.Bool()))
+	case reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uint:
+		sb.WriteString(strconv.FormatUint(rv.Uint(), 10))
+	case reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64, reflect.Int:
+		sb.WriteString(strconv.FormatInt(rv.Int(), 10))
+	case reflect.Float32:
+		sb.WriteString(formatFloat(rv.Float(), 32))
+	case reflect.Float64:
+		sb.WriteString(formatFloat(rv.Float(), 64))
+	case reflect.String:
+		sb.WriteString(quote(rv.String(), '"'))
+	case reflect.Slice, reflect.Array:
+		return encodeItems(sb, "[", "]", rv, func(rv reflect.Value) error {
+			return encode(sb, rv)
+		})
+	case reflect.Pointer:
+		if rv.IsNil() {
+			sb.WriteString("none")
+			return nil
+		}
+		return encodeCase(sb, "some", rv.Elem())
+	case reflect.Struct:
+		return encodeRecord(sb, rv)
+	default:
+		return fmt.Errorf("unsupported type: %s", rv.Type())
+	}
+	return nil
+}
+
+func encodeItems(sb *strings.Builder, open, close string, rv reflect.Value, encodeItem func(rv reflect.Value) error) error {
+	sb.WriteString(open)
+	for i := 0; i < rv.Len(); i++ {
+		if i > 0 {
+			sb.WriteString(", ")
+		}
+		err := encodeItem(rv.Index(i))
+		if err != nil {
+			return fmt.Errorf("item #%d: %w", i, err)
+		}
+	}
+	sb.WriteString(close)
+	return nil
+}
+
+func encodeRecord(sb *strings.Builder, rv reflect.Value) error {
+	fields := recordFields(rv.Type())
+	if len(fields) == 0 {
+		return fmt.Errorf("unsupported record without fields: %s", rv.Type())
+	}
+
+	sb.WriteString("{")
+	for i, field := range fields {
+		if i > 0 {
+			sb.WriteString(", ")
+		}
+		err := encodeLabel(sb, field.name)
+		if err != nil {
+			return fmt.Errorf("field %s: %w", rv.Type().Field(field.index).Name, err)
+		}
+		sb.WriteString(": ")
+		err = encode(sb, rv.Field(field.index))
+		if err != nil {
+			return fmt.Errorf("field %s: %w", rv.Type().Field(field.index).Name, err)
+		}
+	}
+	sb.WriteString("}")
+	return nil
+}
+
+func encodeVariant(sb *strings.Builder, variant Variant) error {
+	caseLabel, err := label(variant.Case)
+	if err == nil {
+		err = encodeCase(sb, caseLabel, reflect.ValueOf(variant.Payload))
+	}
+	if err != nil {
+		return fmt.Errorf("case %s: %w", variant.Case, err)
+	}
+	return nil
+}
+
+// encodeCase encodes the case of variants, options and results, nil interfaces and struct{} are cases without payload
+func encodeCase(sb *strings.Builder, caseLabel string, payload reflect.Value) error {
+	sb.WriteString(caseLabel)
+	if !payload.IsValid() || payload.Type() == unitType {
+		return nil
+	}
+	sb.WriteString("(")
+	err := encode(sb, payload)
+	if err != nil {
+		return err
+	}
+	sb.WriteString(")")
+	return nil
+}
+
+func encodeLabel(sb *strings.Builder, name string) error {
+	l, err := label(name)
+	if err != nil {
+		return err
+	}
+	sb.WriteString(l)
+	return nil
+}
+
+func formatFloat(f float64, bitSize int) string {
+	switch {
+	case math.IsNaN(f):
+		return "nan"
+	case math.IsInf(f, 1):
+		return "inf"
+	case math.IsInf(f, -1):
+		return "-inf"
+	default:
+		return strconv.FormatFloat(f, 'g', -1, bitSize)
+	}
+}
+
+// quote quotes the string or char with WAVE escapes, the other quote character is not escaped
+func quote(s string, quoteChar rune) string {
+	sb := &strings.Builder{}
+	sb.WriteRune(quoteChar)
+	for _, r := range s {
+		switch {
+		case r == quoteChar || r == '\\':
+			sb.WriteRune('\\')
+			sb.WriteRune(r)
+		case r == '\n':
+			sb.WriteString(`\n`)
+		case r == '\r':
+			sb.WriteString(`\r`)
+		case r == '\t':
+			sb.WriteString(`\t`)
+		case !unicode.IsPrint(r):
+			_, _ = fmt.Fprintf(sb, `\u{%x}`, r)
+		default:
+			sb.WriteRune(r)
+		}
+	}
+	sb.WriteRune(quoteChar)
+	return sb.String()
+}
+
+type recordField struct {
+	index int
+	name  string
+}
+
+// recordFields returns the fields mapped to record fields, with their names
+func recordFields(t reflect.Type) []recordField {
+	var fields []recordField
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		name := field.Tag.Get("wave")
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			name = KebabCase(field.Name)
+		}
+		fields = append(fields, recordField{index: i, name: name})
+	}
+	return fields
+}
@@ -0,0 +1,146 @@
+// Package wave encodes go values as WAVE (WebAssembly Value Encoding) strings, which are used for the function
+// arguments of golem-cli, e.g. `golem-cli worker invoke-and-await --arg '{name: "a", count: some(1)}'`, and decodes
+// the JSON results of golem-cli's invocations into go values.
+//
+// The go types are mapped to WIT types as follows:
+//   - bool, the sized integer types, float32, float64 and string to the primitive types, int and uint to s64 and u64
+//   - Char to char (rune cannot be told apart from int32, which is mapped to s32)
+//   - structs to records, the field names are the kebab-case names of the exported fields, or set with the `wave` tag,
+//     fields tagged with `wave:"-"` are skipped
+//   - slices and arrays to lists, pointers to options, nil is none
+//   - Result to results, Variant and the types implementing VariantMarshaler to variants
+//   - Enum and Flags to enums and flags
+//   - Tuple to tuples of the dynamic values
+//
+// The same types can be decoded, and decoding to an interface{} value returns the JSON value decoded with
+// json.Decoder.UseNumber.
+package wave
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// Char is a WIT char
+type Char rune
+
+// Enum is the case name of a WIT enum
+type Enum string
+
+// Flags are the names of the set flags of WIT flags
+type Flags []string
+
+// Tuple is a tuple of dynamic values
+type Tuple []any
+
+// Variant is a case of a WIT variant, the Payload is nil for cases without payload. Decoded variants have the JSON
+// value of the payload.
+type Variant struct {
+	Case    string
+	Payload any
+}
+
+// VariantMarshaler is implemented by go types representing WIT variants
+type VariantMarshaler interface {
+	MarshalWaveVariant() (Variant, error)
+}
+
+// Result is a WIT result, with the Ok or Err payload set depending on IsErr. Payloads of type struct{} or nil
+// interfaces are mapped to cases without payload.
+type Result[T, E any] struct {
+	Ok    T
+	Err   E
+	IsErr bool
+}
+
+// Ok returns an ok Result
+func Ok[T, E any](value T) Result[T, E] {
+	return Result[T, E]{Ok: value}
+}
+
+// Err returns an error Result
+func Err[T, E any](err E) Result[T, E] {
+	return Result[T, E]{Err: err, IsErr: true}
+}
+
+func (r Result[T, E]) marshalWaveResult() (bool, any) {
+	if r.IsErr {
+		return true, r.Err
+	}
+	return false, r.Ok
+}
+
+func (r *Result[T, E]) decodeWaveResult(isErr bool, payload any) error {
+	*r = Result[T, E]{IsErr: isErr}
+	if isErr {
+		return decodePayload(payload, &r.Err)
+	}
+	return decodePayload(payload, &r.Ok)
+}
+
+type resultMarshaler interface {
+	marshalWaveResult() (bool, any)
+}
+
+type resultDecoder interface {
+	decodeWaveResult(isErr bool, payload any) error
+}
+
+// keywords are the WAVE keywords, which must be escaped with % when used as labels
+var keywords = map[string]bool{
+	"true":  true,
+	"false": true,
+	"inf":   true,
+	"nan":   true,
+	"some":  true,
+	"none":  true,
+	"ok":    true,
+	"err":   true,
+}
+
+// KebabCase converts a go identifier to kebab-case, e.g. WorkerURI to worker-uri
+func KebabCase(name string) string {
+	runes := []rune(name)
+	var sb strings.Builder
+	for i, r := range runes {
+		if unicode.IsUpper(r) {
+			// a new word starts at an upper case letter, which follows a lower case letter or a digit, or which is
+			// followed by a lower case letter in an acronym
+			if i > 0 && (!unicode.IsUpper(runes[i-1]) || i+1 < len(runes) && unicode.IsLower(runes[i+1])) {
+				sb.WriteByte('-')
+			}
+			r = unicode.ToLower(r)
+		}
+		sb.WriteRune(r)
+	}
+	return sb.String()
+}
+
+// label returns the WAVE label for the kebab-case name, escaping the keywords
+func label(name string) (string, error) {
+	if !isLabel(name) {
+		return "", fmt.Errorf("invalid label: %q", name)
+	}
+	if keywords[name] {
+		return "%" + name, nil
+	}
+	return name, nil
+}
+
+func isLabel(name string) bool {
+	if name == "" {
+		return false
+	}
+	for _, word := range strings.Split(name, "-") {
+		if word == "" || !unicode.IsLetter(rune(word[0])) {
+			return false
+		}
+		for _, r := range word {
+			if r > unicode.MaxASCII || !unicode.IsLower(r) && !unicode.IsDigit(r) {
+				return false
+			}
+		}
+	}
+	return true
+}
@@ -0,0 +1,192 @@
+package wave
+
+import (
+	"math"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type entry struct {
+	Name     string
+	MaxCount *uint64
+	WorkerID string `wave:"id"`
+	Cached   bool   `wave:"-"`
+}
+
+// status is a variant with the cases ok, failed(string) and retry(u32)
+type status struct {
+	failed string
+	retry  uint32
+}
+
+func (s status) MarshalWaveVariant() (Variant, error) {
+	switch {
+	case s.failed != "":
+		return Variant{Case: "failed", Payload: s.failed}, nil
+	case s.retry > 0:
+		return Variant{Case: "retry", Payload: s.retry}, nil
+	default:
+		return Variant{Case: "ok"}, nil
+	}
+}
+
+func TestEncode(t *testing.T) {
+	tests := []struct {
+		name     string
+		value    any
+		expected string
+	}{
+		{name: "bool", value: true, expected: `true`},
+		{name: "u64", value: uint64(math.MaxUint64), expected: `18446744073709551615`},
+		{name: "s8", value: int8(-8), expected: `-8`},
+		{name: "float32", value: float32(1.5), expected: `1.5`},
+		{name: "float64", value: 1e100, expected: `1e+100`},
+		{name: "nan", value: math.NaN(), expected: `nan`},
+		{name: "inf", value: math.Inf(-1), expected: `-inf`},
+		{name: "char", value: Char('\''), expected: `'\''`},
+		{name: "string", value: "a \"b\"\n'c'\\\x00é", expected: `"a \"b\"\n'c'\\\u{0}é"`},
+		{name: "record", value: entry{Name: "a", MaxCount: ptr(uint64(3)), WorkerID: "w", Cached: true}, expected: `{name: "a", max-count: some(3), id: "w"}`},
+		{name: "none field", value: entry{Name: "a"}, expected: `{name: "a", max-count: none, id: ""}`},
+		{name: "list", value: []uint32{1, 2}, expected: `[1, 2]`},
+		{name: "empty list", value: []string{}, expected: `[]`},
+		{name: "array", value: [2]bool{true, false}, expected: `[true, false]`},
+		{name: "some none", value: ptr((*string)(nil)), expected: `some(none)`},
+		{name: "tuple", value: Tuple{uint8(1), "a", []Enum{"b"}}, expected: `(1, "a", [b])`},
+		{name: "ok", value: Ok[uint64, string](1), expected: `ok(1)`},
+		{name: "unit ok", value: Ok[struct{}, string](struct{}{}), expected: `ok`},
+		{name: "err", value: Err[struct{}](Variant{Case: "not-found", Payload: "x"}), expected: `err(not-found("x"))`},
+		{name: "variant", value: status{retry: 2}, expected: `retry(2)`},
+		{name: "keyword case", value: status{}, expected: `%ok`},
+		{name: "enum", value: Enum("fast"), expected: `fast`},
+		{name: "flags", value: Flags{"read", "none"}, expected: `{read, %none}`},
+		{name: "empty flags", value: Flags{}, expected: `{}`},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			actual, err := Encode(test.value)
+			if err != nil {
+				t.Fatalf("%+v", err)
+			}
+			if actual != test.expected {
+				t.Fatalf("Expected: %s, actual: %s", test.expected, actual)
+			}
+		})
+	}
+}
+
+func TestEncodeErrors(t *testing.T) {
+	_, err := Encode(map[string]int{})
+	expectErrorContains(t, err, "unsupported type: map[string]int")
+
+	_, err = Encode([]any{nil})
+	expectErrorContains(t, err, "item #0: unsupported nil value")
+
+	_, err = Encode(Enum("Fast"))
+	expectErrorContains(t, err, `invalid label: "Fast"`)
+
+	_, err = Encode(struct{ hidden bool }{})
+	expectErrorContains(t, err, "unsupported record without fields")
+
+	_, err = EncodeArgs(uint64(1), Variant{Case: "a-", Payload: 1})
+	expectErrorContains(t, err, `arg #1: wave: encode wave.Variant: case a-: invalid label: "a-"`)
+}
+
+func TestKebabCase(t *testing.T) {
+	tests := map[string]string{
+		"Name":        "name",
+		"MaxCount":    "max-count",
+		"WorkerURI":   "worker-uri",
+		"URIValue":    "uri-value",
+		"ComponentV2": "component-v2",
+	}
+	for name, expected := range tests {
+		if actual := KebabCase(name); actual != expected {
+			t.Errorf("Expected kebab-case of %s: %s, actual: %s", name, expected, actual)
+		}
+	}
+}
+
+func TestDecodeResults(t *testing.T) {
+	output := `{
+		"typ": [],
+		"value": [
+			18446744073709551615,
+			{"name": "a", "max-count": 3, "id": "w"},
+			[{"name": "b", "max-count": null, "id": ""}],
+			{"err": {"not-found": "x"}},
+			{"ok": null},
+			"fast",
+			["read"],
+			[1, "a"],
+			"é",
+			{"nested": {"a": [1.5]}}
+		]
+	}`
+
+	var (
+		count   uint64
+		e       entry
+		entries []entry
+		result  Result[uint64, Variant]
+		unit    Result[struct{}, Variant]
+		mode    Enum
+		flags   Flags
+		tuple   Tuple
+		char    Char
+		dynamic any
+	)
+	err := DecodeResults(output, &count, &e, &entries, &result, &unit, &mode, &flags, &tuple, &char, &dynamic)
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+
+	actual := []any{count, e, entries, result, unit, mode, flags, len(tuple), char}
+	expected := []any{
+		uint64(math.MaxUint64),
+		entry{Name: "a", MaxCount: ptr(uint64(3)), WorkerID: "w"},
+		[]entry{{Name: "b"}},
+		Err[uint64](Variant{Case: "not-found", Payload: "x"}),
+		Ok[struct{}, Variant](struct{}{}),
+		Enum("fast"),
+		Flags{"read"},
+		2,
+		Char('é'),
+	}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Fatalf("Expected: %#v, actual: %#v", expected, actual)
+	}
+	if _, ok := dynamic.(map[string]any)["nested"]; !ok {
+		t.Fatalf("Unexpected dynamic value: %#v", dynamic)
+	}
+}
+
+func TestDecodeErrors(t *testing.T) {
+	var count uint8
+	expectErrorContains(t, DecodeResults(`{"value": [256]}`, &count), "result #0 to *uint8: expected uint8, got 256")
+	expectErrorContains(t, DecodeResults(`{"value": [1, 2]}`, &count), "expected 1 result(s), got 2")
+	expectErrorContains(t, DecodeResults(`{"error": "failed"}`, &count), "missing value in output")
+	expectErrorContains(t, DecodeResults(`{"value": [1]}`, count), "target must be a non-nil pointer")
+
+	var e entry
+	expectErrorContains(t, DecodeJSON(`{"name": 1}`, &e), "field name: expected string, got number")
+
+	var result Result[uint64, string]
+	expectErrorContains(t, DecodeJSON(`{"some": 1}`, &result), "expected ok or err result case, got some")
+	expectErrorContains(t, DecodeJSON(`{"ok": null}`, &result), "expected number, got null")
+}
+
+func ptr[T any](value T) *T {
+	return &value
+}
+
+func expectErrorContains(t *testing.T, err error, expected string) {
+	t.Helper()
+	if err == nil {
+		t.Fatalf("Expected error containing %q", expected)
+	}
+	if !strings.Contains(err.Error(), expected) {
+		t.Fatalf("Expected error containing %q, actual: %q", expected, err.Error())
+	}
+}
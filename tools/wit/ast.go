@@ -0,0 +1,298 @@
+// Package wit parses WIT (WebAssembly Interface Type) definitions into an AST with source positions.
+//
+// The parser covers the WIT syntax used by the component model 0.2 era definitions: packages, top-level and
+// interface level use statements, interfaces, worlds, type aliases, records, variants, enums, flags, resources
+// and functions. Feature gates (e.g. @since) are skipped.
+package wit
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Pos is a position in a WIT source file, lines and columns are 1-based
+type Pos struct {
+	Filename string
+	Line     int
+	Column   int
+}
+
+func (p Pos) String() string {
+	return fmt.Sprintf("%s:%d:%d", p.Filename, p.Line, p.Column)
+}
+
+// File is a parsed WIT source file
+type File struct {
+	Pos        Pos
+	Package    *PackageName
+	Uses       []*Use
+	Interfaces []*Interface
+	Worlds     []*World
+}
+
+// Interface returns the interface by name, or nil if it is not defined in the file
+func (f *File) Interface(name string) *Interface {
+	for _, iface := range f.Interfaces {
+		if iface.Name == name {
+			return iface
+		}
+	}
+	return nil
+}
+
+// World returns the world by name, or nil if it is not defined in the file
+func (f *File) World(name string) *World {
+	for _, world := range f.Worlds {
+		if world.Name == name {
+			return world
+		}
+	}
+	return nil
+}
+
+// Package is a WIT package parsed from all the files of a directory
+type Package struct {
+	Name  *PackageName
+	Files []*File
+}
+
+// Interface returns the interface by name from any of the package's files, or nil if it is not defined
+func (p *Package) Interface(name string) *Interface {
+	for _, file := range p.Files {
+		if iface := file.Interface(name); iface != nil {
+			return iface
+		}
+	}
+	return nil
+}
+
+// World returns the world by name from any of the package's files, or nil if it is not defined
+func (p *Package) World(name string) *World {
+	for _, file := range p.Files {
+		if world := file.World(name); world != nil {
+			return world
+		}
+	}
+	return nil
+}
+
+// PackageName is a package reference, e.g. wasi:io@0.2.0
+type PackageName struct {
+	Pos       Pos
+	Namespace string
+	Name      string
+	Version   string
+}
+
+func (p *PackageName) String() string {
+	s := p.Namespace + ":" + p.Name
+	if p.Version != "" {
+		s += "@" + p.Version
+	}
+	return s
+}
+
+// UsePath is an interface reference, e.g. wasi:io/streams@0.2.0, or a local interface name if Package is nil
+type UsePath struct {
+	Pos       Pos
+	Package   *PackageName
+	Interface string
+}
+
+func (p *UsePath) String() string {
+	if p.Package == nil {
+		return p.Interface
+	}
+	s := p.Package.Namespace + ":" + p.Package.Name + "/" + p.Interface
+	if p.Package.Version != "" {
+		s += "@" + p.Package.Version
+	}
+	return s
+}
+
+// Use is a use statement, either a top-level one (use path as alias;) or an interface or world level one,
+// which imports names (use path.{name, name as alias};)
+type Use struct {
+	Pos   Pos
+	Path  *UsePath
+	Alias string
+	Names []*UseName
+}
+
+// UseName is an imported name of a use statement, or a renamed item of an include statement
+type UseName struct {
+	Pos   Pos
+	Name  string
+	Alias string
+}
+
+// Interface is a named or inline interface definition
+type Interface struct {
+	Pos      Pos
+	Docs     string
+	Name     string
+	Uses     []*Use
+	TypeDefs []*TypeDef
+	Funcs    []*Func
+}
+
+// TypeDef returns the type definition by name, or nil if it is not defined in the interface
+func (i *Interface) TypeDef(name string) *TypeDef {
+	for _, typeDef := range i.TypeDefs {
+		if typeDef.Name == name {
+			return typeDef
+		}
+	}
+	return nil
+}
+
+// Func returns the function by name, or nil if it is not defined in the interface
+func (i *Interface) Func(name string) *Func {
+	for _, f := range i.Funcs {
+		if f.Name == name {
+			return f
+		}
+	}
+	return nil
+}
+
+// World is a world definition
+type World struct {
+	Pos      Pos
+	Docs     string
+	Name     string
+	Uses     []*Use
+	TypeDefs []*TypeDef
+	Imports  []*WorldItem
+	Exports  []*WorldItem
+	Includes []*Include
+}
+
+// WorldItem is an import or export of a world, exactly one of Path, Func and Interface is set
+type WorldItem struct {
+	Pos       Pos
+	Docs      string
+	Name      string
+	Path      *UsePath
+	Func      *Func
+	Interface *Interface
+}
+
+// Include is an include statement of a world
+type Include struct {
+	Pos  Pos
+	Path *UsePath
+	With []*UseName
+}
+
+// TypeDefKind is the kind of a type definition
+type TypeDefKind int
+
+const (
+	TypeDefKindAlias TypeDefKind = iota
+	TypeDefKindRecord
+	TypeDefKindVariant
+	TypeDefKindEnum
+	TypeDefKindFlags
+	TypeDefKindResource
+)
+
+func (k TypeDefKind) String() string {
+	switch k {
+	case TypeDefKindAlias:
+		return "type"
+	case TypeDefKindRecord:
+		return "record"
+	case TypeDefKindVariant:
+		return "variant"
+	case TypeDefKindEnum:
+		return "enum"
+	case TypeDefKindFlags:
+		return "flags"
+	case TypeDefKindResource:
+		return "resource"
+	default:
+		return fmt.Sprintf("TypeDefKind(%d)", int(k))
+	}
+}
+
+// TypeDef is a type definition, depending on the Kind:
+//   - TypeDefKindAlias uses Type,
+//   - TypeDefKindRecord uses Fields,
+//   - TypeDefKindVariant, TypeDefKindEnum and TypeDefKindFlags use Cases (enum and flags cases have no Type),
+//   - TypeDefKindResource uses Funcs.
+type TypeDef struct {
+	Pos    Pos
+	Docs   string
+	Kind   TypeDefKind
+	Name   string
+	Type   *Type
+	Fields []*Field
+	Cases  []*Case
+	Funcs  []*Func
+}
+
+// Field is a record field
+type Field struct {
+	Pos  Pos
+	Docs string
+	Name string
+	Type *Type
+}
+
+// Case is a variant, enum or flags case, Type is only set for variant cases with payload
+type Case struct {
+	Pos  Pos
+	Docs string
+	Name string
+	Type *Type
+}
+
+// FuncKind is the kind of a function
+type FuncKind int
+
+const (
+	FuncKindFreestanding FuncKind = iota
+	FuncKindMethod
+	FuncKindStatic
+	FuncKindConstructor
+)
+
+// Func is a function definition, resource methods, static functions and constructors are also funcs
+type Func struct {
+	Pos     Pos
+	Docs    string
+	Kind    FuncKind
+	Name    string
+	Params  []*Param
+	Results []*Param
+}
+
+// Param is a named function parameter or result, a single unnamed result has an empty Name
+type Param struct {
+	Pos  Pos
+	Name string
+	Type *Type
+}
+
+// Type is a type reference, e.g. u64, list<string>, result<_, error> or a named type,
+// Params holds the type parameters, where nil stands for the omitted (_) ok type of results
+type Type struct {
+	Pos    Pos
+	Name   string
+	Params []*Type
+}
+
+func (t *Type) String() string {
+	if t == nil {
+		return "_"
+	}
+	if len(t.Params) == 0 {
+		return t.Name
+	}
+	params := make([]string, len(t.Params))
+	for i, param := range t.Params {
+		params[i] = param.String()
+	}
+	return t.Name + "<" + strings.Join(params, ", ") + ">"
+}
@@ -0,0 +1,189 @@
+package wit
+
+import (
+	"fmt"
+	"strings"
+)
+
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenIdent
+	tokenPunct
+)
+
+type token struct {
+	Kind tokenKind
+	Text string
+	// Escaped is set for identifiers prefixed with %, which are never keywords, e.g. %type
+	Escaped bool
+	Pos     Pos
+	Docs    string
+}
+
+func (t token) String() string {
+	switch t.Kind {
+	case tokenEOF:
+		return "end of file"
+	case tokenIdent:
+		return fmt.Sprintf("identifier %q", t.Text)
+	default:
+		return fmt.Sprintf("%q", t.Text)
+	}
+}
+
+// Error is a WIT syntax error
+type Error struct {
+	Pos Pos
+	Msg string
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("%s: %s", e.Pos, e.Msg)
+}
+
+type lexer struct {
+	filename string
+	src      string
+	offset   int
+	line     int
+	column   int
+	docs     []string
+}
+
+func newLexer(filename string, src []byte) *lexer {
+	return &lexer{
+		filename: filename,
+		src:      string(src),
+		line:     1,
+		column:   1,
+	}
+}
+
+func (l *lexer) pos() Pos {
+	return Pos{Filename: l.filename, Line: l.line, Column: l.column}
+}
+
+func (l *lexer) errorf(pos Pos, format string, args ...any) {
+	panic(&Error{Pos: pos, Msg: fmt.Sprintf(format, args...)})
+}
+
+func (l *lexer) advance(n int) {
+	for i := 0; i < n && l.offset < len(l.src); i++ {
+		if l.src[l.offset] == '\n' {
+			l.line++
+			l.column = 1
+		} else {
+			l.column++
+		}
+		l.offset++
+	}
+}
+
+func (l *lexer) skipSpaceAndComments() {
+	for l.offset < len(l.src) {
+		rest := l.src[l.offset:]
+		switch {
+		case rest[0] == ' ' || rest[0] == '\t' || rest[0] == '\r' || rest[0] == '\n':
+			l.advance(1)
+		case strings.HasPrefix(rest, "///"):
+			end := strings.IndexByte(rest, '\n')
+			if end < 0 {
+				end = len(rest)
+			}
+			l.docs = append(l.docs, strings.TrimSpace(rest[3:end]))
+			l.advance(end)
+		case strings.HasPrefix(rest, "//"):
+			end := strings.IndexByte(rest, '\n')
+			if end < 0 {
+				end = len(rest)
+			}
+			l.advance(end)
+		case strings.HasPrefix(rest, "/*"):
+			pos := l.pos()
+			end := strings.Index(rest, "*/")
+			if end < 0 {
+				l.errorf(pos, "unterminated block comment")
+			}
+			if strings.HasPrefix(rest, "/**") && !strings.HasPrefix(rest, "/**/") {
+				for _, line := range strings.Split(rest[3:end], "\n") {
+					l.docs = append(l.docs, strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(line), "*")))
+				}
+			}
+			l.advance(end + 2)
+		default:
+			return
+		}
+	}
+}
+
+func isIdentChar(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '-' || c == '_'
+}
+
+func (l *lexer) next() token {
+	l.skipSpaceAndComments()
+
+	tok := token{Pos: l.pos(), Docs: strings.TrimSpace(strings.Join(l.docs, "\n"))}
+	l.docs = nil
+
+	if l.offset >= len(l.src) {
+		tok.Kind = tokenEOF
+		return tok
+	}
+
+	c := l.src[l.offset]
+	switch {
+	case c == '%' || c != '-' && isIdentChar(c):
+		start := l.offset
+		if c == '%' {
+			start++
+			l.advance(1)
+			tok.Escaped = true
+		}
+		for l.offset < len(l.src) && isIdentChar(l.src[l.offset]) {
+			l.advance(1)
+		}
+		tok.Kind = tokenIdent
+		tok.Text = l.src[start:l.offset]
+		if tok.Text == "" {
+			l.errorf(tok.Pos, "missing identifier after %%")
+		}
+	case strings.HasPrefix(l.src[l.offset:], "->"):
+		tok.Kind = tokenPunct
+		tok.Text = "->"
+		l.advance(2)
+	case strings.IndexByte("{}()<>,;:.=@/*", c) >= 0:
+		tok.Kind = tokenPunct
+		tok.Text = string(c)
+		l.advance(1)
+	default:
+		l.errorf(tok.Pos, "unexpected character %q", c)
+	}
+
+	return tok
+}
+
+// version reads a semver version directly after an @ token, as versions cannot be tokenized as identifiers,
+// the trailing dot is not consumed, so the version can be followed by the .{ of a use statement
+func (l *lexer) version() (string, Pos) {
+	pos := l.pos()
+	start := l.offset
+	end := start
+	for end < len(l.src) {
+		c := l.src[end]
+		if !(isIdentChar(c) || c == '.' || c == '+') {
+			break
+		}
+		end++
+	}
+	for end > start && l.src[end-1] == '.' {
+		end--
+	}
+	if end == start {
+		l.errorf(pos, "missing version")
+	}
+	l.advance(end - start)
+	return l.src[start:end], pos
+}
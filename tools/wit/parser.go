@@ -0,0 +1,572 @@
+package wit
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+// ParseFile parses the WIT file at path
+func ParseFile(path string) (*File, error) {
+	src, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("wit: read failed for %s, %w", path, err)
+	}
+	return Parse(path, src)
+}
+
+// ParseDir parses all the WIT files of a directory (not including subdirectories, like deps) as a single package
+func ParseDir(dir string) (*Package, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.wit"))
+	if err != nil {
+		return nil, fmt.Errorf("wit: glob failed for %s, %w", dir, err)
+	}
+	if len(paths) == 0 {
+		return nil, fmt.Errorf("wit: no WIT files found in %s", dir)
+	}
+	sort.Strings(paths)
+
+	pkg := &Package{}
+	for _, path := range paths {
+		file, err := ParseFile(path)
+		if err != nil {
+			return nil, err
+		}
+
+		if file.Package != nil {
+			if pkg.Name == nil {
+				pkg.Name = file.Package
+			} else if pkg.Name.String() != file.Package.String() {
+				return nil, &Error{
+					Pos: file.Package.Pos,
+					Msg: fmt.Sprintf("package %s conflicts with package %s declared at %s", file.Package, pkg.Name, pkg.Name.Pos),
+				}
+			}
+		}
+
+		pkg.Files = append(pkg.Files, file)
+	}
+
+	return pkg, nil
+}
+
+// Parse parses WIT source, the filename is only used for positions
+func Parse(filename string, src []byte) (file *File, err error) {
+	p := &parser{lexer: newLexer(filename, src)}
+
+	defer func() {
+		if r := recover(); r != nil {
+			if witErr, ok := r.(*Error); ok {
+				file = nil
+				err = witErr
+				return
+			}
+			panic(r)
+		}
+	}()
+
+	return p.parseFile(), nil
+}
+
+type parser struct {
+	lexer  *lexer
+	peeked *token
+}
+
+func (p *parser) peek() token {
+	if p.peeked == nil {
+		tok := p.lexer.next()
+		p.peeked = &tok
+	}
+	return *p.peeked
+}
+
+func (p *parser) next() token {
+	tok := p.peek()
+	p.peeked = nil
+	return tok
+}
+
+func (p *parser) errorf(pos Pos, format string, args ...any) {
+	panic(&Error{Pos: pos, Msg: fmt.Sprintf(format, args...)})
+}
+
+// is reports whether the next token is the keyword or punctuation, escaped identifiers never match
+func (p *parser) is(text string) bool {
+	tok := p.peek()
+	return tok.Kind != tokenEOF && !tok.Escaped && tok.Text == text
+}
+
+func (p *parser) isPunct(text string) bool {
+	tok := p.peek()
+	return tok.Kind == tokenPunct && tok.Text == text
+}
+
+func (p *parser) accept(text string) bool {
+	if p.is(text) {
+		p.next()
+		return true
+	}
+	return false
+}
+
+func (p *parser) expect(text string) token {
+	tok := p.next()
+	if tok.Kind == tokenEOF || tok.Escaped || tok.Text != text {
+		p.errorf(tok.Pos, "expected %q, got %s", text, tok)
+	}
+	return tok
+}
+
+func (p *parser) ident() token {
+	tok := p.next()
+	if tok.Kind != tokenIdent {
+		p.errorf(tok.Pos, "expected identifier, got %s", tok)
+	}
+	return tok
+}
+
+// version reads a version after an already consumed @
+func (p *parser) version() string {
+	if p.peeked != nil {
+		p.errorf(p.peeked.Pos, "unexpected %s before version", *p.peeked)
+	}
+	version, _ := p.lexer.version()
+	return version
+}
+
+// skipGates skips feature gates like @since(version = 0.2.0) or @unstable(feature = name) before items
+func (p *parser) skipGates() {
+	for p.isPunct("@") {
+		p.next()
+		p.ident()
+		p.expect("(")
+		depth := 1
+		for depth > 0 {
+			tok := p.next()
+			switch {
+			case tok.Kind == tokenEOF:
+				p.errorf(tok.Pos, "unterminated feature gate")
+			case tok.Kind == tokenPunct && tok.Text == "(":
+				depth++
+			case tok.Kind == tokenPunct && tok.Text == ")":
+				depth--
+			case tok.Kind == tokenPunct && tok.Text == "@":
+				p.version()
+			}
+		}
+	}
+}
+
+func (p *parser) parseFile() *File {
+	file := &File{Pos: Pos{Filename: p.lexer.filename, Line: 1, Column: 1}}
+
+	p.skipGates()
+	if p.is("package") {
+		p.next()
+		file.Package = p.parsePackageName()
+		p.expect(";")
+	}
+
+	for {
+		p.skipGates()
+		tok := p.peek()
+		if tok.Kind == tokenEOF {
+			return file
+		}
+
+		switch {
+		case p.is("use"):
+			file.Uses = append(file.Uses, p.parseTopLevelUse())
+		case p.is("interface"):
+			file.Interfaces = append(file.Interfaces, p.parseInterface())
+		case p.is("world"):
+			file.Worlds = append(file.Worlds, p.parseWorld())
+		default:
+			p.errorf(tok.Pos, "expected use, interface or world, got %s", tok)
+		}
+	}
+}
+
+// parsePackageName parses namespace:name[@version] after the already consumed package keyword
+func (p *parser) parsePackageName() *PackageName {
+	namespace := p.ident()
+	p.expect(":")
+	name := p.ident()
+	pkg := &PackageName{Pos: namespace.Pos, Namespace: namespace.Text, Name: name.Text}
+	if p.isPunct("@") {
+		p.next()
+		pkg.Version = p.version()
+	}
+	return pkg
+}
+
+// parseUsePath parses an interface reference: name or namespace:package/name[@version]
+func (p *parser) parseUsePath() *UsePath {
+	first := p.ident()
+	if !p.isPunct(":") {
+		return &UsePath{Pos: first.Pos, Interface: first.Text}
+	}
+	p.expect(":")
+	return p.parseQualifiedUsePath(first)
+}
+
+// parseQualifiedUsePath parses package/name[@version] after the already consumed namespace and colon
+func (p *parser) parseQualifiedUsePath(namespace token) *UsePath {
+	pkgName := p.ident()
+	p.expect("/")
+	iface := p.ident()
+	path := &UsePath{
+		Pos:       namespace.Pos,
+		Package:   &PackageName{Pos: namespace.Pos, Namespace: namespace.Text, Name: pkgName.Text},
+		Interface: iface.Text,
+	}
+	if p.isPunct("@") {
+		p.next()
+		path.Package.Version = p.version()
+	}
+	return path
+}
+
+func (p *parser) parseTopLevelUse() *Use {
+	use := &Use{Pos: p.expect("use").Pos}
+	use.Path = p.parseUsePath()
+	if p.accept("as") {
+		use.Alias = p.ident().Text
+	}
+	p.expect(";")
+	return use
+}
+
+// parseUse parses an interface or world level use statement: use path.{name, name as alias};
+func (p *parser) parseUse() *Use {
+	use := &Use{Pos: p.expect("use").Pos}
+	use.Path = p.parseUsePath()
+	p.expect(".")
+	p.expect("{")
+	for !p.isPunct("}") {
+		name := p.ident()
+		useName := &UseName{Pos: name.Pos, Name: name.Text}
+		if p.accept("as") {
+			useName.Alias = p.ident().Text
+		}
+		use.Names = append(use.Names, useName)
+		if !p.accept(",") {
+			break
+		}
+	}
+	p.expect("}")
+	p.expect(";")
+	return use
+}
+
+func (p *parser) parseInterface() *Interface {
+	tok := p.expect("interface")
+	name := p.ident()
+	iface := p.parseInterfaceBody(tok.Pos, tok.Docs)
+	iface.Name = name.Text
+	return iface
+}
+
+// parseInterfaceBody parses the { ... } part of named and inline interfaces
+func (p *parser) parseInterfaceBody(pos Pos, docs string) *Interface {
+	iface := &Interface{Pos: pos, Docs: docs}
+	p.expect("{")
+	for {
+		p.skipGates()
+		tok := p.peek()
+		if tok.Kind == tokenPunct && tok.Text == "}" {
+			p.next()
+			return iface
+		}
+
+		switch {
+		case tok.Kind == tokenEOF:
+			p.errorf(tok.Pos, "unterminated interface")
+		case p.is("use"):
+			iface.Uses = append(iface.Uses, p.parseUse())
+		case p.isTypeDefStart():
+			iface.TypeDefs = append(iface.TypeDefs, p.parseTypeDef())
+		default:
+			name := p.ident()
+			p.expect(":")
+			iface.Funcs = append(iface.Funcs, p.parseFunc(name, FuncKindFreestanding))
+		}
+	}
+}
+
+func (p *parser) isTypeDefStart() bool {
+	tok := p.peek()
+	if tok.Kind != tokenIdent || tok.Escaped {
+		return false
+	}
+	switch tok.Text {
+	case "type", "record", "variant", "enum", "flags", "resource":
+		return true
+	default:
+		return false
+	}
+}
+
+func (p *parser) parseTypeDef() *TypeDef {
+	tok := p.next()
+	name := p.ident()
+	typeDef := &TypeDef{Pos: tok.Pos, Docs: tok.Docs, Name: name.Text}
+
+	switch tok.Text {
+	case "type":
+		typeDef.Kind = TypeDefKindAlias
+		p.expect("=")
+		typeDef.Type = p.parseType()
+		p.expect(";")
+	case "record":
+		typeDef.Kind = TypeDefKindRecord
+		p.parseList("{", "}", func() {
+			fieldName := p.ident()
+			p.expect(":")
+			typeDef.Fields = append(typeDef.Fields, &Field{
+				Pos:  fieldName.Pos,
+				Docs: fieldName.Docs,
+				Name: fieldName.Text,
+				Type: p.parseType(),
+			})
+		})
+	case "variant":
+		typeDef.Kind = TypeDefKindVariant
+		p.parseList("{", "}", func() {
+			caseName := p.ident()
+			c := &Case{Pos: caseName.Pos, Docs: caseName.Docs, Name: caseName.Text}
+			if p.accept("(") {
+				c.Type = p.parseType()
+				p.expect(")")
+			}
+			typeDef.Cases = append(typeDef.Cases, c)
+		})
+	case "enum":
+		typeDef.Kind = TypeDefKindEnum
+		p.parseList("{", "}", func() {
+			caseName := p.ident()
+			typeDef.Cases = append(typeDef.Cases, &Case{Pos: caseName.Pos, Docs: caseName.Docs, Name: caseName.Text})
+		})
+	case "flags":
+		typeDef.Kind = TypeDefKindFlags
+		p.parseList("{", "}", func() {
+			flagName := p.ident()
+			typeDef.Cases = append(typeDef.Cases, &Case{Pos: flagName.Pos, Docs: flagName.Docs, Name: flagName.Text})
+		})
+	case "resource":
+		typeDef.Kind = TypeDefKindResource
+		if p.accept(";") {
+			return typeDef
+		}
+		p.expect("{")
+		for !p.accept("}") {
+			p.skipGates()
+			if p.peek().Kind == tokenEOF {
+				p.errorf(p.peek().Pos, "unterminated resource")
+			}
+			if p.is("constructor") {
+				typeDef.Funcs = append(typeDef.Funcs, p.parseConstructor())
+				continue
+			}
+			funcName := p.ident()
+			p.expect(":")
+			kind := FuncKindMethod
+			if p.accept("static") {
+				kind = FuncKindStatic
+			}
+			typeDef.Funcs = append(typeDef.Funcs, p.parseFunc(funcName, kind))
+		}
+	}
+
+	return typeDef
+}
+
+// parseList parses a comma separated list with an optional trailing comma between open and close
+func (p *parser) parseList(open, close string, item func()) {
+	p.expect(open)
+	for !p.isPunct(close) {
+		if p.peek().Kind == tokenEOF {
+			p.errorf(p.peek().Pos, "expected %q, got %s", close, p.peek())
+		}
+		item()
+		if !p.accept(",") {
+			break
+		}
+	}
+	p.expect(close)
+}
+
+func (p *parser) parseConstructor() *Func {
+	tok := p.expect("constructor")
+	f := &Func{Pos: tok.Pos, Docs: tok.Docs, Kind: FuncKindConstructor, Name: "constructor"}
+	f.Params = p.parseParams()
+	p.expect(";")
+	return f
+}
+
+// parseFunc parses func(params) [-> results]; after the already consumed name and colon
+func (p *parser) parseFunc(name token, kind FuncKind) *Func {
+	f := &Func{Pos: name.Pos, Docs: name.Docs, Kind: kind, Name: name.Text}
+	p.parseFuncType(f)
+	p.expect(";")
+	return f
+}
+
+func (p *parser) parseFuncType(f *Func) {
+	p.expect("func")
+	f.Params = p.parseParams()
+	if p.accept("->") {
+		if p.isPunct("(") {
+			f.Results = p.parseParams()
+		} else {
+			resultType := p.parseType()
+			f.Results = []*Param{{Pos: resultType.Pos, Type: resultType}}
+		}
+	}
+}
+
+func (p *parser) parseParams() []*Param {
+	var params []*Param
+	p.parseList("(", ")", func() {
+		name := p.ident()
+		p.expect(":")
+		params = append(params, &Param{Pos: name.Pos, Name: name.Text, Type: p.parseType()})
+	})
+	return params
+}
+
+func (p *parser) parseType() *Type {
+	tok := p.ident()
+	t := &Type{Pos: tok.Pos, Name: tok.Text}
+	if tok.Escaped {
+		// named type, e.g. %list
+		return t
+	}
+
+	switch tok.Text {
+	case "list", "option", "tuple", "borrow", "own", "future", "stream":
+		if tok.Text == "future" || tok.Text == "stream" {
+			if !p.isPunct("<") {
+				return t
+			}
+		}
+		p.parseList("<", ">", func() {
+			if p.peek().Kind == tokenIdent && isNumber(p.peek().Text) {
+				// fixed size lists: list<u8, 4>
+				size := p.next()
+				t.Params = append(t.Params, &Type{Pos: size.Pos, Name: size.Text})
+				return
+			}
+			t.Params = append(t.Params, p.parseType())
+		})
+	case "result":
+		if !p.isPunct("<") {
+			return t
+		}
+		p.parseList("<", ">", func() {
+			if p.is("_") {
+				p.next()
+				t.Params = append(t.Params, nil)
+				return
+			}
+			t.Params = append(t.Params, p.parseType())
+		})
+	}
+
+	return t
+}
+
+func isNumber(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] < '0' || s[i] > '9' {
+			return false
+		}
+	}
+	return s != ""
+}
+
+func (p *parser) parseWorld() *World {
+	tok := p.expect("world")
+	name := p.ident()
+	world := &World{Pos: tok.Pos, Docs: tok.Docs, Name: name.Text}
+
+	p.expect("{")
+	for {
+		p.skipGates()
+		tok := p.peek()
+		if tok.Kind == tokenPunct && tok.Text == "}" {
+			p.next()
+			return world
+		}
+
+		switch {
+		case tok.Kind == tokenEOF:
+			p.errorf(tok.Pos, "unterminated world")
+		case p.is("use"):
+			world.Uses = append(world.Uses, p.parseUse())
+		case p.is("import"):
+			world.Imports = append(world.Imports, p.parseWorldItem())
+		case p.is("export"):
+			world.Exports = append(world.Exports, p.parseWorldItem())
+		case p.is("include"):
+			world.Includes = append(world.Includes, p.parseInclude())
+		case p.isTypeDefStart():
+			world.TypeDefs = append(world.TypeDefs, p.parseTypeDef())
+		default:
+			p.errorf(tok.Pos, "expected use, import, export, include or type definition, got %s", tok)
+		}
+	}
+}
+
+// parseWorldItem parses the import or export forms:
+//   - import path;
+//   - import name: func(...);
+//   - import name: interface { ... }
+func (p *parser) parseWorldItem() *WorldItem {
+	tok := p.next()
+	item := &WorldItem{Pos: tok.Pos, Docs: tok.Docs}
+
+	first := p.ident()
+	if !p.isPunct(":") {
+		item.Path = &UsePath{Pos: first.Pos, Interface: first.Text}
+		item.Name = item.Path.String()
+		p.expect(";")
+		return item
+	}
+	p.expect(":")
+
+	switch {
+	case p.is("func"):
+		item.Name = first.Text
+		item.Func = &Func{Pos: first.Pos, Docs: tok.Docs, Kind: FuncKindFreestanding, Name: first.Text}
+		p.parseFuncType(item.Func)
+		p.expect(";")
+	case p.is("interface"):
+		ifaceTok := p.next()
+		item.Name = first.Text
+		item.Interface = p.parseInterfaceBody(ifaceTok.Pos, tok.Docs)
+		item.Interface.Name = first.Text
+	default:
+		item.Path = p.parseQualifiedUsePath(first)
+		item.Name = item.Path.String()
+		p.expect(";")
+	}
+	return item
+}
+
+func (p *parser) parseInclude() *Include {
+	include := &Include{Pos: p.expect("include").Pos}
+	include.Path = p.parseUsePath()
+	if p.accept("with") {
+		p.parseList("{", "}", func() {
+			name := p.ident()
+			p.expect("as")
+			include.With = append(include.With, &UseName{Pos: name.Pos, Name: name.Text, Alias: p.ident().Text})
+		})
+		return include
+	}
+	p.expect(";")
+	return include
+}
@@ -0,0 +1,265 @@
+package wit
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const testWit = `package golem:component-one@1.0.0;
+
+use wasi:io/poll@0.2.0 as poll;
+
+/// The API of component one
+interface component-one-api {
+  use golem:rpc/types@0.1.0.{uri as golem-rpc-uri, wit-value};
+  use types.{counter};
+
+  type name = string;
+
+  /// A counter entry
+  record entry {
+    /// The name of the counter
+    name: name,
+    value: option<u64>,
+  }
+
+  variant status { ok, failed(string) }
+  enum mode { fast, slow }
+  flags permissions { read, write }
+
+  resource worker {
+    constructor(location: golem-rpc-uri);
+    add: func(value: u64);
+    create: static func() -> worker;
+  }
+
+  resource handle;
+
+  add: func(value: u64) -> result<_, string>;
+  get: func() -> u64;
+  pair: func() -> (a: list<tuple<u8, s8>>, b: result);
+}
+
+world component-one {
+  import golem:api/host@0.2.0;
+  import wasi:cli/environment@0.2.0;
+  import component-one-api;
+  import log: func(message: string);
+  export inline: interface {
+    get: func() -> borrow<handle>;
+  }
+  include wasi:cli/imports@0.2.0 with { environment as env }
+  include other;
+
+  export component-one-api;
+}
+`
+
+func TestParse(t *testing.T) {
+	file, err := Parse("test.wit", []byte(testWit))
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+
+	expectEqual(t, "package", file.Package.String(), "golem:component-one@1.0.0")
+	expectEqual(t, "top-level use", file.Uses[0].Path.String(), "wasi:io/poll@0.2.0")
+	expectEqual(t, "top-level use alias", file.Uses[0].Alias, "poll")
+
+	iface := file.Interface("component-one-api")
+	if iface == nil {
+		t.Fatalf("missing interface")
+	}
+	expectEqual(t, "interface docs", iface.Docs, "The API of component one")
+	expectEqual(t, "interface pos", iface.Pos.String(), "test.wit:6:1")
+
+	expectEqual(t, "use path", iface.Uses[0].Path.String(), "golem:rpc/types@0.1.0")
+	expectEqual(t, "use name", iface.Uses[0].Names[0].Name, "uri")
+	expectEqual(t, "use alias", iface.Uses[0].Names[0].Alias, "golem-rpc-uri")
+	expectEqual(t, "use second name", iface.Uses[0].Names[1].Name, "wit-value")
+	expectEqual(t, "local use path", iface.Uses[1].Path.String(), "types")
+
+	expectEqual(t, "alias type", iface.TypeDef("name").Type.String(), "string")
+
+	entry := iface.TypeDef("entry")
+	expectEqual(t, "record kind", entry.Kind.String(), "record")
+	expectEqual(t, "record docs", entry.Docs, "A counter entry")
+	expectEqual(t, "record field docs", entry.Fields[0].Docs, "The name of the counter")
+	expectEqual(t, "record field type", entry.Fields[1].Type.String(), "option<u64>")
+	expectEqual(t, "record field pos", entry.Fields[1].Pos.String(), "test.wit:16:5")
+
+	status := iface.TypeDef("status")
+	expectEqual(t, "variant case without type", status.Cases[0].Type.String(), "_")
+	expectEqual(t, "variant case type", status.Cases[1].Type.String(), "string")
+	expectEqual(t, "enum case", iface.TypeDef("mode").Cases[1].Name, "slow")
+	expectEqual(t, "flags case", iface.TypeDef("permissions").Cases[1].Name, "write")
+
+	worker := iface.TypeDef("worker")
+	if len(worker.Funcs) != 3 {
+		t.Fatalf("expected 3 resource funcs, got %d", len(worker.Funcs))
+	}
+	if worker.Funcs[0].Kind != FuncKindConstructor || worker.Funcs[1].Kind != FuncKindMethod || worker.Funcs[2].Kind != FuncKindStatic {
+		t.Fatalf("unexpected resource func kinds")
+	}
+	expectEqual(t, "constructor param", worker.Funcs[0].Params[0].Type.String(), "golem-rpc-uri")
+	expectEqual(t, "empty resource", iface.TypeDef("handle").Kind.String(), "resource")
+
+	expectEqual(t, "func result", iface.Func("add").Results[0].Type.String(), "result<_, string>")
+	expectEqual(t, "named results", iface.Func("pair").Results[0].Type.String(), "list<tuple<u8, s8>>")
+	expectEqual(t, "named result name", iface.Func("pair").Results[1].Name, "b")
+	expectEqual(t, "bare result", iface.Func("pair").Results[1].Type.String(), "result")
+
+	world := file.World("component-one")
+	if world == nil {
+		t.Fatalf("missing world")
+	}
+	var imports []string
+	for _, imp := range world.Imports {
+		imports = append(imports, imp.Name)
+	}
+	expectEqual(t, "world imports", strings.Join(imports, ", "), "golem:api/host@0.2.0, wasi:cli/environment@0.2.0, component-one-api, log")
+	expectEqual(t, "world import pos", world.Imports[1].Pos.String(), "test.wit:38:3")
+	expectEqual(t, "world import func", world.Imports[3].Func.Params[0].Name, "message")
+	expectEqual(t, "world inline export", world.Exports[0].Interface.Func("get").Results[0].Type.String(), "borrow<handle>")
+	expectEqual(t, "world export", world.Exports[1].Path.String(), "component-one-api")
+	expectEqual(t, "world include", world.Includes[0].Path.String(), "wasi:cli/imports@0.2.0")
+	expectEqual(t, "world include with", world.Includes[0].With[0].Alias, "env")
+	expectEqual(t, "world local include", world.Includes[1].Path.String(), "other")
+}
+
+func TestParseEscapedIdentifiers(t *testing.T) {
+	src := `package a:b;
+
+interface %interface {
+  %type: func();
+  %use: func(%record: %list) -> %result;
+  resource %resource {
+    %constructor: func();
+  }
+}
+
+world w {
+  import %import: func();
+  export %interface;
+}
+`
+	file, err := Parse("escaped.wit", []byte(src))
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+
+	iface := file.Interface("interface")
+	if iface == nil {
+		t.Fatalf("missing interface")
+	}
+	if len(iface.Funcs) != 2 || len(iface.Uses) != 0 || len(iface.TypeDefs) != 1 {
+		t.Fatalf("expected 2 funcs and 1 type definition, got: %d funcs, %d uses, %d type definitions",
+			len(iface.Funcs), len(iface.Uses), len(iface.TypeDefs))
+	}
+	expectEqual(t, "escaped func name", iface.Funcs[0].Name, "type")
+	expectEqual(t, "escaped use func name", iface.Funcs[1].Name, "use")
+	expectEqual(t, "escaped param name", iface.Func("use").Params[0].Name, "record")
+	expectEqual(t, "escaped param type", iface.Func("use").Params[0].Type.String(), "list")
+	expectEqual(t, "escaped result type", iface.Func("use").Results[0].Type.String(), "result")
+	resource := iface.TypeDef("resource")
+	if len(resource.Funcs) != 1 || resource.Funcs[0].Kind != FuncKindMethod {
+		t.Fatalf("expected escaped constructor method, got: %+v", resource.Funcs)
+	}
+
+	world := file.World("w")
+	expectEqual(t, "escaped world import", world.Imports[0].Name, "import")
+	expectEqual(t, "escaped world export", world.Exports[0].Path.String(), "interface")
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		src      string
+		expected string
+	}{
+		{src: "package a:b;\ninterface x {\n  f: func(;\n}", expected: "err.wit:3:11: expected identifier, got \";\""},
+		{src: "package a:b;\nworld w {\n  import a:b/c@;\n}", expected: "err.wit:3:16: missing version"},
+		{src: "package a:b;\ninterface x {", expected: "err.wit:2:14: unterminated interface"},
+		{src: "package a:b;\nrecord x {}", expected: "err.wit:2:1: expected use, interface or world, got identifier \"record\""},
+		{src: "package a:b;\n/* comment", expected: "err.wit:2:1: unterminated block comment"},
+		{src: "package a:b;\nworld w {\n  %import a:b/c;\n}", expected: "err.wit:3:3: expected use, import, export, include or type definition, got identifier \"import\""},
+	}
+
+	for _, test := range tests {
+		_, err := Parse("err.wit", []byte(test.src))
+		if err == nil {
+			t.Fatalf("expected error for:\n%s", test.src)
+		}
+		expectEqual(t, "error", err.Error(), test.expected)
+	}
+}
+
+// TestParseRepoWit parses all the WIT files of the project, including the shared WIT dependencies
+func TestParseRepoWit(t *testing.T) {
+	root := filepath.Join("..", "..")
+
+	var paths []string
+	for _, dir := range []string{"wit-deps", "components", "component-template"} {
+		err := filepath.WalkDir(filepath.Join(root, dir), func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if d.IsDir() && d.Name() == "binding" {
+				return filepath.SkipDir
+			}
+			if !d.IsDir() && filepath.Ext(path) == ".wit" {
+				paths = append(paths, path)
+			}
+			return nil
+		})
+		if err != nil {
+			t.Fatalf("%+v", err)
+		}
+	}
+	if len(paths) == 0 {
+		t.Fatalf("no WIT files found")
+	}
+
+	for _, path := range paths {
+		_, err := ParseFile(path)
+		if err != nil {
+			t.Errorf("%+v", err)
+		}
+	}
+
+	depDirs, err := os.ReadDir(filepath.Join(root, "wit-deps"))
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	for _, depDir := range depDirs {
+		if !depDir.IsDir() {
+			continue
+		}
+		pkg, err := ParseDir(filepath.Join(root, "wit-deps", depDir.Name()))
+		if err != nil {
+			t.Errorf("%+v", err)
+			continue
+		}
+		if pkg.Name == nil {
+			t.Errorf("%s: missing package name", depDir.Name())
+		}
+	}
+
+	pkg, err := ParseDir(filepath.Join(root, "wit-deps", "wasm-rpc"))
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	expectEqual(t, "wasm-rpc package", pkg.Name.String(), "golem:rpc@0.1.0")
+	witNode := pkg.Interface("types").TypeDef("wit-node")
+	expectEqual(t, "wit-node case", witNode.Cases[1].Type.String(), "tuple<u32, option<node-index>>")
+	futureInvokeResult := pkg.Interface("types").TypeDef("future-invoke-result")
+	expectEqual(t, "future-invoke-result get", futureInvokeResult.Funcs[1].Results[0].Type.String(), "option<result<wit-value, rpc-error>>")
+}
+
+func expectEqual(t *testing.T, what, actual, expected string) {
+	t.Helper()
+	if actual != expected {
+		t.Fatalf("Expected %s: %q, actual: %q", what, expected, actual)
+	}
+}
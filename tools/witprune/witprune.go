@@ -0,0 +1,317 @@
+// Package witprune finds the imports of a component's world, which are not used by the component's go code.
+//
+// The used imports are found by mapping every imported interface to the go identifiers generated for it by
+// wit-bindgen tiny-go (e.g. wasi:io/poll@0.2.0 to WasiIo0_2_0_PollPoll), then checking which of them are referenced
+// from the component's binding package.
+//
+// golem-go's binding package only contains the go side of the cgo bindings (binding.go and binding.h), the C
+// functions it calls are provided by the component's own generated bindings. So every interface declared by golem-go's
+// binding package has to stay imported, even if it is only used through golem-go (e.g. golemhost or the std
+// initialization) or lib, see GolemGoBindingIdents.
+package witprune
+
+import (
+	"fmt"
+	"go/ast"
+	"go/build/constraint"
+	"go/parser"
+	"go/token"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"golem-go-project/tools/wit"
+)
+
+// Deps are the WIT packages of a component's wit/deps dir, by namespace:name
+type Deps map[string]*wit.Package
+
+// LoadDeps parses all the packages in depsDir
+func LoadDeps(depsDir string) (Deps, error) {
+	entries, err := os.ReadDir(depsDir)
+	if err != nil {
+		return nil, fmt.Errorf("witprune: read dir failed for %s, %w", depsDir, err)
+	}
+
+	deps := make(Deps)
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		pkg, err := wit.ParseDir(filepath.Join(depsDir, entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+		if pkg.Name == nil {
+			return nil, fmt.Errorf("witprune: missing package name in %s", filepath.Join(depsDir, entry.Name()))
+		}
+		deps[pkg.Name.Namespace+":"+pkg.Name.Name] = pkg
+	}
+
+	return deps, nil
+}
+
+// Interface returns the definition of the interface referenced by path, or nil if it is not found
+func (d Deps) Interface(path *wit.UsePath) *wit.Interface {
+	if path.Package == nil {
+		return nil
+	}
+	pkg, ok := d[path.Package.Namespace+":"+path.Package.Name]
+	if !ok {
+		return nil
+	}
+	return pkg.Interface(path.Interface)
+}
+
+// GoIdents returns the go identifiers generated by wit-bindgen tiny-go for the imported interface: the functions,
+// types, resource constructors and static resource functions, methods of resources are not included
+func GoIdents(path *wit.UsePath, iface *wit.Interface) []string {
+	prefix := goIdentPrefix(path)
+
+	var idents []string
+	for _, use := range iface.Uses {
+		for _, name := range use.Names {
+			alias := name.Name
+			if name.Alias != "" {
+				alias = name.Alias
+			}
+			idents = append(idents, prefix+pascal(alias))
+		}
+	}
+	for _, typeDef := range iface.TypeDefs {
+		idents = append(idents, prefix+pascal(typeDef.Name))
+		for _, f := range typeDef.Funcs {
+			switch f.Kind {
+			case wit.FuncKindConstructor:
+				idents = append(idents, "New"+pascal(typeDef.Name))
+			case wit.FuncKindStatic:
+				idents = append(idents, "Static"+pascal(typeDef.Name)+pascal(f.Name))
+			}
+		}
+	}
+	for _, f := range iface.Funcs {
+		idents = append(idents, prefix+pascal(f.Name))
+	}
+
+	return idents
+}
+
+// goIdentPrefix returns the prefix of the generated identifiers, e.g. WasiIo0_2_0_Poll or WasiBlobstoreTypes
+func goIdentPrefix(path *wit.UsePath) string {
+	prefix := pascal(path.Package.Namespace) + pascal(path.Package.Name)
+	if path.Package.Version != "" {
+		prefix += strings.NewReplacer(".", "_", "-", "_", "+", "_").Replace(path.Package.Version) + "_"
+	}
+	return prefix + pascal(path.Interface)
+}
+
+// pascal converts a WIT kebab-case name to PascalCase
+func pascal(name string) string {
+	var s strings.Builder
+	for _, part := range strings.Split(name, "-") {
+		if part == "" {
+			continue
+		}
+		s.WriteString(strings.ToUpper(part[:1]))
+		s.WriteString(part[1:])
+	}
+	return s.String()
+}
+
+// UsedIdents returns the identifiers referenced from the package with bindingImportPath by the go files in dir,
+// test files and files only built with the hosttest tag are ignored
+func UsedIdents(dir, bindingImportPath string) (map[string]bool, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.go"))
+	if err != nil {
+		return nil, fmt.Errorf("witprune: glob failed for %s, %w", dir, err)
+	}
+
+	used := make(map[string]bool)
+	fset := token.NewFileSet()
+	for _, path := range paths {
+		if strings.HasSuffix(path, "_test.go") {
+			continue
+		}
+
+		file, err := parser.ParseFile(fset, path, nil, parser.ParseComments)
+		if err != nil {
+			return nil, fmt.Errorf("witprune: parse failed for %s, %w", path, err)
+		}
+		if !includedInBuild(file) {
+			continue
+		}
+
+		localName := ""
+		for _, imp := range file.Imports {
+			importPath, err := strconv.Unquote(imp.Path.Value)
+			if err != nil || importPath != bindingImportPath {
+				continue
+			}
+			localName = filepath.Base(importPath)
+			if imp.Name != nil {
+				localName = imp.Name.Name
+			}
+		}
+		if localName == "" {
+			continue
+		}
+
+		ast.Inspect(file, func(node ast.Node) bool {
+			if sel, ok := node.(*ast.SelectorExpr); ok {
+				if x, ok := sel.X.(*ast.Ident); ok && x.Name == localName {
+					used[sel.Sel.Name] = true
+				}
+			}
+			return true
+		})
+	}
+
+	return used, nil
+}
+
+// GolemGoBindingImportPath is the import path of golem-go's binding package
+const GolemGoBindingImportPath = "github.com/golemcloud/golem-go/binding"
+
+// GolemGoBindingIdents returns the identifiers, which have to be generated into the component's binding, so the cgo
+// symbols of golem-go's binding package are provided: the identifiers declared by golem-go's binding package, and the
+// ones referenced from it by the go packages in libDirs
+func GolemGoBindingIdents(libDirs ...string) (map[string]bool, error) {
+	output, err := exec.Command("go", "list", "-f", "{{.Dir}}", GolemGoBindingImportPath).Output()
+	if err != nil {
+		return nil, fmt.Errorf("witprune: go list failed for %s, %w", GolemGoBindingImportPath, err)
+	}
+
+	idents, err := DeclaredIdents(strings.TrimSpace(string(output)))
+	if err != nil {
+		return nil, err
+	}
+	for _, libDir := range libDirs {
+		used, err := UsedIdents(libDir, GolemGoBindingImportPath)
+		if err != nil {
+			return nil, err
+		}
+		for ident := range used {
+			idents[ident] = true
+		}
+	}
+
+	return idents, nil
+}
+
+// DeclaredIdents returns the exported top-level identifiers declared by the go files in dir, methods and test files
+// are ignored
+func DeclaredIdents(dir string) (map[string]bool, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.go"))
+	if err != nil {
+		return nil, fmt.Errorf("witprune: glob failed for %s, %w", dir, err)
+	}
+
+	declared := make(map[string]bool)
+	fset := token.NewFileSet()
+	for _, path := range paths {
+		if strings.HasSuffix(path, "_test.go") {
+			continue
+		}
+
+		file, err := parser.ParseFile(fset, path, nil, parser.SkipObjectResolution)
+		if err != nil {
+			return nil, fmt.Errorf("witprune: parse failed for %s, %w", path, err)
+		}
+
+		for _, decl := range file.Decls {
+			switch decl := decl.(type) {
+			case *ast.FuncDecl:
+				if decl.Recv == nil && decl.Name.IsExported() {
+					declared[decl.Name.Name] = true
+				}
+			case *ast.GenDecl:
+				for _, spec := range decl.Specs {
+					switch spec := spec.(type) {
+					case *ast.TypeSpec:
+						if spec.Name.IsExported() {
+							declared[spec.Name.Name] = true
+						}
+					case *ast.ValueSpec:
+						for _, name := range spec.Names {
+							if name.IsExported() {
+								declared[name.Name] = true
+							}
+						}
+					}
+				}
+			}
+		}
+	}
+
+	return declared, nil
+}
+
+// includedInBuild evaluates the build constraint of the file, with all tags set except hosttest
+func includedInBuild(file *ast.File) bool {
+	for _, group := range file.Comments {
+		if group.Pos() >= file.Package {
+			break
+		}
+		for _, comment := range group.List {
+			if !constraint.IsGoBuild(comment.Text) {
+				continue
+			}
+			expr, err := constraint.Parse(comment.Text)
+			if err != nil {
+				return true
+			}
+			return expr.Eval(func(tag string) bool { return tag != "hosttest" })
+		}
+	}
+	return true
+}
+
+// UnusedImports returns the interface imports of the world, none of whose generated identifiers are in used.
+// Imports of local interfaces, functions and inline interfaces, and the imports listed in keep are never reported.
+func UnusedImports(world *wit.World, deps Deps, used map[string]bool, keep map[string]bool) ([]*wit.WorldItem, error) {
+	var unused []*wit.WorldItem
+	for _, imp := range world.Imports {
+		if imp.Path == nil || imp.Path.Package == nil || keep[imp.Name] {
+			continue
+		}
+
+		iface := deps.Interface(imp.Path)
+		if iface == nil {
+			return nil, fmt.Errorf("witprune: %s: interface not found in deps: %s", imp.Pos, imp.Name)
+		}
+
+		isUsed := false
+		for _, ident := range GoIdents(imp.Path, iface) {
+			if used[ident] {
+				isUsed = true
+				break
+			}
+		}
+		if !isUsed {
+			unused = append(unused, imp)
+		}
+	}
+
+	return unused, nil
+}
+
+// Prune removes the lines of the imports from the WIT source, lines which also contain other items are kept
+func Prune(src []byte, imports []*wit.WorldItem) []byte {
+	lines := make(map[int]bool)
+	for _, imp := range imports {
+		lines[imp.Pos.Line] = true
+	}
+
+	srcLines := strings.SplitAfter(string(src), "\n")
+	var pruned strings.Builder
+	for i, line := range srcLines {
+		if lines[i+1] && strings.Count(line, ";") == 1 {
+			continue
+		}
+		pruned.WriteString(line)
+	}
+
+	return []byte(pruned.String())
+}
@@ -0,0 +1,209 @@
+package witprune
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"golem-go-project/tools/wit"
+)
+
+const testWit = `package golem:component-one;
+
+interface component-one-api {
+  add: func(value: u64);
+}
+
+world component-one {
+  import wasi:io/poll@0.2.0;
+  import wasi:blobstore/blobstore;
+  import wasi:keyvalue/eventual@0.1.0;
+  import golem:rpc/types@0.1.0;
+  import log: func(message: string);
+
+  export component-one-api;
+}
+`
+
+const testGo = `//go:build !hosttest
+
+package main
+
+import (
+	kv "golem-go-project/components/component-one/binding"
+)
+
+func get() {
+	bucket := kv.StaticBucketOpenBucket("bucket").Unwrap()
+	kv.WasiKeyvalue0_1_0_EventualGet(bucket, "key")
+}
+`
+
+const testHostTestGo = `//go:build hosttest
+
+package main
+
+import (
+	"golem-go-project/components/component-one/binding"
+)
+
+var _ = binding.WasiIo0_2_0_PollPoll
+`
+
+func TestGoIdents(t *testing.T) {
+	deps := mustLoadRepoDeps(t)
+
+	tests := []struct {
+		path     string
+		expected []string
+	}{
+		{path: "wasi:io/poll@0.2.0", expected: []string{"WasiIo0_2_0_PollPoll", "WasiIo0_2_0_PollPollable"}},
+		{path: "wasi:blobstore/types", expected: []string{"WasiBlobstoreTypesIncomingValue", "StaticOutgoingValueNewOutgoingValue"}},
+		{path: "wasi:keyvalue/types@0.1.0", expected: []string{"WasiKeyvalue0_1_0_TypesBucket", "StaticBucketOpenBucket"}},
+		{path: "golem:rpc/types@0.1.0", expected: []string{"GolemRpc0_1_0_TypesUri", "NewWasmRpc"}},
+	}
+
+	for _, test := range tests {
+		path := mustParseImportPath(t, test.path)
+		iface := deps.Interface(path)
+		if iface == nil {
+			t.Fatalf("missing interface: %s", test.path)
+		}
+
+		idents := make(map[string]bool)
+		for _, ident := range GoIdents(path, iface) {
+			idents[ident] = true
+		}
+		for _, expected := range test.expected {
+			if !idents[expected] {
+				t.Fatalf("Expected go ident for %s: %s, actual: %v", test.path, expected, idents)
+			}
+		}
+	}
+}
+
+func TestUnusedImports(t *testing.T) {
+	dir := t.TempDir()
+	mustWriteFile(t, filepath.Join(dir, "binding.go"), testGo)
+	mustWriteFile(t, filepath.Join(dir, "binding_hosttest.go"), testHostTestGo)
+
+	used, err := UsedIdents(dir, "golem-go-project/components/component-one/binding")
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	if used["WasiIo0_2_0_PollPoll"] {
+		t.Fatalf("Expected identifiers of hosttest files to be ignored")
+	}
+
+	file, err := wit.Parse("component-one.wit", []byte(testWit))
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+
+	unused, err := UnusedImports(file.World("component-one"), mustLoadRepoDeps(t), used, map[string]bool{"golem:rpc/types@0.1.0": true})
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+
+	var unusedNames []string
+	for _, imp := range unused {
+		unusedNames = append(unusedNames, imp.Name)
+	}
+	expectEqual(t, "unused imports", strings.Join(unusedNames, ", "), "wasi:io/poll@0.2.0, wasi:blobstore/blobstore")
+
+	pruned := string(Prune([]byte(testWit), unused))
+	if strings.Contains(pruned, "wasi:io/poll") || strings.Contains(pruned, "wasi:blobstore") {
+		t.Fatalf("Expected unused imports to be pruned:\n%s", pruned)
+	}
+	if !strings.Contains(pruned, "import wasi:keyvalue/eventual@0.1.0;") || !strings.Contains(pruned, "import log:") {
+		t.Fatalf("Expected used imports to be kept:\n%s", pruned)
+	}
+
+	if _, err := wit.Parse("component-one.wit", []byte(pruned)); err != nil {
+		t.Fatalf("%+v", err)
+	}
+}
+
+// TestUnusedImportsKeepsGolemGoImports checks that the imports used only through golem-go and lib are kept, as their
+// C functions are called by golem-go's binding package, e.g. component-three uses none of them from its own binding
+func TestUnusedImportsKeepsGolemGoImports(t *testing.T) {
+	root := filepath.Join("..", "..")
+	golemGoIdents, err := GolemGoBindingIdents(filepath.Join(root, "lib", "rpc"), filepath.Join(root, "lib", "host"))
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	for _, ident := range []string{"GolemApi0_2_0_HostGetSelfMetadata", "NewWasmRpc", "WasiLoggingLoggingLog"} {
+		if !golemGoIdents[ident] {
+			t.Fatalf("Expected golem-go binding ident: %s", ident)
+		}
+	}
+
+	componentDir := filepath.Join(root, "components", "component-three")
+	used, err := UsedIdents(componentDir, "golem-go-project/components/component-three/binding")
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	for ident := range golemGoIdents {
+		used[ident] = true
+	}
+
+	file, err := wit.ParseFile(filepath.Join(componentDir, "wit", "component-three.wit"))
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	unused, err := UnusedImports(file.World("component-three"), mustLoadRepoDeps(t), used, nil)
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+
+	unusedNames := make(map[string]bool)
+	for _, imp := range unused {
+		unusedNames[imp.Name] = true
+	}
+	for _, kept := range []string{
+		"golem:api/host@0.2.0",
+		"golem:rpc/types@0.1.0",
+		"wasi:logging/logging",
+		"wasi:filesystem/preopens@0.2.0",
+		"wasi:cli/environment@0.2.0",
+		"wasi:http/outgoing-handler@0.2.0",
+	} {
+		if unusedNames[kept] {
+			t.Fatalf("Expected import to be kept: %s, unused: %v", kept, unusedNames)
+		}
+	}
+}
+
+func mustLoadRepoDeps(t *testing.T) Deps {
+	t.Helper()
+	deps, err := LoadDeps(filepath.Join("..", "..", "wit-deps"))
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	return deps
+}
+
+func mustParseImportPath(t *testing.T, path string) *wit.UsePath {
+	t.Helper()
+	file, err := wit.Parse("path.wit", []byte("package a:b;\nworld w {\n  import "+path+";\n}\n"))
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	return file.Worlds[0].Imports[0].Path
+}
+
+func mustWriteFile(t *testing.T, path, contents string) {
+	t.Helper()
+	err := os.WriteFile(path, []byte(contents), 0644)
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+}
+
+func expectEqual(t *testing.T, what, actual, expected string) {
+	t.Helper()
+	if actual != expected {
+		t.Fatalf("Expected %s: %q, actual: %q", what, expected, actual)
+	}
+}
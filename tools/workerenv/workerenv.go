@@ -0,0 +1,115 @@
+// Package workerenv computes the env vars needed by the workers of the components from the worker to worker RPC
+// dependency graph and the URNs of the deployed components.
+//
+// The workers of a component need the component id env vars of its dependencies (e.g. COMPONENT_THREE_ID for
+// component-three), and also the ones of the transitive dependencies: the workers created by RPC calls inherit the
+// env vars of the calling worker, so e.g. the component-two workers created by component-one calls can only call
+// component-three, if the component-one worker has COMPONENT_THREE_ID too.
+package workerenv
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"golem-go-project/lib/cfg/components"
+)
+
+// Graph is the RPC dependency graph, the dependencies by component name
+type Graph map[string][]string
+
+// Dependencies returns the direct and transitive dependencies of the component in name order, cycles are allowed
+func (g Graph) Dependencies(componentName string) []string {
+	seen := map[string]bool{}
+	var visit func(componentName string)
+	visit = func(componentName string) {
+		for _, dependency := range g[componentName] {
+			if !seen[dependency] {
+				seen[dependency] = true
+				visit(dependency)
+			}
+		}
+	}
+	visit(componentName)
+	delete(seen, componentName)
+
+	dependencies := make([]string, 0, len(seen))
+	for dependency := range seen {
+		dependencies = append(dependencies, dependency)
+	}
+	sort.Strings(dependencies)
+	return dependencies
+}
+
+// IDEnvVar returns the name of the env var of the component's id from the generated components.IDEnvVars, e.g.
+// COMPONENT_ONE_ID for component-one, and false for unknown components
+func IDEnvVar(componentName string) (string, bool) {
+	envVar, ok := components.IDEnvVars[componentName]
+	return envVar, ok
+}
+
+// Env are env vars by name
+type Env map[string]string
+
+// Compute returns the component id env vars needed by the workers of the component, for its direct and transitive
+// dependencies. The componentURNs are the URNs (or ids) of the deployed components by component name, as printed by
+// golem-cli, only the ones of the dependencies are required.
+func Compute(graph Graph, componentURNs map[string]string, componentName string) (Env, error) {
+	env := Env{}
+	var unknown, missing []string
+	for _, dependency := range graph.Dependencies(componentName) {
+		envVar, ok := IDEnvVar(dependency)
+		if !ok {
+			unknown = append(unknown, dependency)
+			continue
+		}
+		urn := componentURNs[dependency]
+		if urn == "" {
+			missing = append(missing, dependency)
+			continue
+		}
+		env[envVar] = urn
+	}
+	if len(unknown) > 0 {
+		return nil, fmt.Errorf("workerenv: compute failed for %s, unknown components (run generateCfgComponents): %s", componentName, strings.Join(unknown, ", "))
+	}
+	if len(missing) > 0 {
+		return nil, fmt.Errorf("workerenv: compute failed for %s, missing component URNs of: %s", componentName, strings.Join(missing, ", "))
+	}
+	return env, nil
+}
+
+// Names returns the names of the env vars in order
+func (e Env) Names() []string {
+	names := make([]string, 0, len(e))
+	for name := range e {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// DotEnv returns the env vars in .env file format, one NAME=value line per env var in name order, the values are
+// quoted if needed
+func (e Env) DotEnv() string {
+	sb := &strings.Builder{}
+	for _, name := range e.Names() {
+		value := e[name]
+		if value != "" && !strings.ContainsAny(value, " \t\r\n\"'#$\\`") {
+			_, _ = fmt.Fprintf(sb, "%s=%s\n", name, value)
+		} else {
+			_, _ = fmt.Fprintf(sb, "%s=%s\n", name, strconv.Quote(value))
+		}
+	}
+	return sb.String()
+}
+
+// CLIArgs returns the env vars as golem-cli args, e.g. --env COMPONENT_TWO_ID=urn:component:... for worker add
+func (e Env) CLIArgs() []string {
+	args := make([]string, 0, 2*len(e))
+	for _, name := range e.Names() {
+		args = append(args, "--env", name+"="+e[name])
+	}
+	return args
+}
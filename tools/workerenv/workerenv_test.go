@@ -0,0 +1,96 @@
+package workerenv
+
+import (
+	"reflect"
+	"testing"
+
+	"golem-go-project/lib/cfg/components"
+)
+
+var testGraph = Graph{
+	"component-one":  {"component-two", "component-three"},
+	"component-two":  {"component-three"},
+	"component-four": {"component-five"},
+	"component-five": {"component-four", "component-one"},
+}
+
+var testURNs = map[string]string{
+	"component-one":   "urn:component:1",
+	"component-two":   "urn:component:2",
+	"component-three": "urn:component:3",
+	"component-four":  "urn:component:4",
+}
+
+func TestDependencies(t *testing.T) {
+	testCases := []struct {
+		componentName string
+		expected      []string
+	}{
+		{"component-one", []string{"component-three", "component-two"}},
+		{"component-two", []string{"component-three"}},
+		{"component-three", []string{}},
+		{"component-four", []string{"component-five", "component-one", "component-three", "component-two"}},
+	}
+
+	for _, testCase := range testCases {
+		actual := testGraph.Dependencies(testCase.componentName)
+		if !reflect.DeepEqual(actual, testCase.expected) {
+			t.Fatalf("Expected dependencies of %s: %v, actual: %v", testCase.componentName, testCase.expected, actual)
+		}
+	}
+}
+
+func TestCompute(t *testing.T) {
+	env, err := Compute(testGraph, testURNs, "component-one")
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	expected := Env{"COMPONENT_TWO_ID": "urn:component:2", "COMPONENT_THREE_ID": "urn:component:3"}
+	if !reflect.DeepEqual(env, expected) {
+		t.Fatalf("Expected: %v, actual: %v", expected, env)
+	}
+
+	env, err = Compute(testGraph, testURNs, "component-three")
+	if err != nil || len(env) != 0 {
+		t.Fatalf("Expected no env vars without dependencies, actual: %v, %+v", env, err)
+	}
+
+	_, err = Compute(testGraph, map[string]string{"component-two": "urn:component:2"}, "component-one")
+	expectedErr := "workerenv: compute failed for component-one, missing component URNs of: component-three"
+	if err == nil || err.Error() != expectedErr {
+		t.Fatalf("Expected error: %s, actual: %+v", expectedErr, err)
+	}
+
+	_, err = Compute(testGraph, testURNs, "component-four")
+	expectedErr = "workerenv: compute failed for component-four, unknown components (run generateCfgComponents): component-five"
+	if err == nil || err.Error() != expectedErr {
+		t.Fatalf("Expected error: %s, actual: %+v", expectedErr, err)
+	}
+}
+
+func TestIDEnvVar(t *testing.T) {
+	if envVar, ok := IDEnvVar("component-one"); !ok || envVar != components.ComponentOneIDEnvVar {
+		t.Fatalf("Expected: %s, actual: %s, %t", components.ComponentOneIDEnvVar, envVar, ok)
+	}
+	if envVar, ok := IDEnvVar("component-five"); ok {
+		t.Fatalf("Expected unknown component, actual: %s", envVar)
+	}
+}
+
+func TestFormats(t *testing.T) {
+	env := Env{"COMPONENT_TWO_ID": "urn:component:2", "COMPONENT_THREE_ID": "urn:component:3", "NAME": "a b"}
+
+	expectedDotEnv := "COMPONENT_THREE_ID=urn:component:3\nCOMPONENT_TWO_ID=urn:component:2\nNAME=\"a b\"\n"
+	if actual := env.DotEnv(); actual != expectedDotEnv {
+		t.Fatalf("Expected: %q, actual: %q", expectedDotEnv, actual)
+	}
+
+	expectedArgs := []string{
+		"--env", "COMPONENT_THREE_ID=urn:component:3",
+		"--env", "COMPONENT_TWO_ID=urn:component:2",
+		"--env", "NAME=a b",
+	}
+	if actual := env.CLIArgs(); !reflect.DeepEqual(actual, expectedArgs) {
+		t.Fatalf("Expected: %q, actual: %q", expectedArgs, actual)
+	}
+}